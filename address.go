@@ -7,13 +7,16 @@
 package hcutil
 
 import (
+	"crypto/subtle"
 	"errors"
 	"fmt"
+	"strings"
 
 	"golang.org/x/crypto/ripemd160"
 
 	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
 	"github.com/HcashOrg/hcd/crypto"
 	"github.com/HcashOrg/hcd/crypto/bliss"
 	"github.com/HcashOrg/hcutil/base58"
@@ -42,8 +45,17 @@ var (
 	// attempts to decode an address without defining which network to decode
 	// for.
 	ErrMissingDefaultNet = errors.New("default net not defined")
+
+	// ErrWrongNetwork describes an error in DecodeAddressDefault where an
+	// address decodes successfully, but for a network other than the
+	// configured default network.
+	ErrWrongNetwork = errors.New("address is for the wrong network")
 )
 
+// defaultNet is the network DecodeAddressDefault decodes addresses for.  It
+// is nil until configured with SetDefaultNet.
+var defaultNet *chaincfg.Params
+
 // encodeAddress returns a human-readable payment address given a ripemd160 hash
 // and netID which encodes the network and address type.  It is used in both
 // pay-to-pubkey-hash (P2PKH) and pay-to-script-hash (P2SH) address encoding.
@@ -149,7 +161,7 @@ func NewAddressPubKey(decoded []byte, net *chaincfg.Params) (Address, error) {
 				append([]byte{toAppend}, decoded[1:]...),
 				net)
 		case chainec.ECTypeEdwards:
-			return NewAddressEdwardsPubKey(decoded, net)
+			return NewAddressEdwardsPubKey(decoded[1:], net)
 		case chainec.ECTypeSecSchnorr:
 			return NewAddressSecSchnorrPubKey(
 				append([]byte{toAppend}, decoded[1:]...),
@@ -163,19 +175,9 @@ func NewAddressPubKey(decoded []byte, net *chaincfg.Params) (Address, error) {
 // DecodeAddress decodes the string encoding of an address and returns
 // the Address if addr is a valid encoding for a known address type
 func DecodeAddress(addr string) (Address, error) {
-	// Switch on decoded length to determine the type.
-	decoded, netID, err := base58.CheckDecode(addr)
+	decoded, netID, net, err := decodeAddressPayload(addr)
 	if err != nil {
-		if err == base58.ErrChecksum {
-			return nil, ErrChecksumMismatch
-		}
-		return nil, fmt.Errorf("decoded address is of unknown format: %v",
-			err.Error())
-	}
-
-	net, err := detectNetworkForAddress(addr)
-	if err != nil {
-		return nil, ErrUnknownAddressType
+		return nil, err
 	}
 
 	switch netID {
@@ -203,6 +205,505 @@ func DecodeAddress(addr string) (Address, error) {
 	}
 }
 
+// registeredNets houses the networks which are checked, in order, when the
+// network of an address or extended key is not known ahead of time.  It holds
+// the default networks along with any networks added with RegisterNet.
+var registeredNets = []*chaincfg.Params{
+	&chaincfg.MainNetParams,
+	&chaincfg.TestNet2Params,
+	&chaincfg.SimNetParams,
+}
+
+// RegisterNet registers the passed network parameters with the chaincfg
+// package and adds the network to those recognized when the network is not
+// known ahead of time, such as by DecodeAddress, PossibleNetworks,
+// DecodeCompactAddress, and hdkeychain.ExtendedKeyNetwork.  The same errors as
+// chaincfg.Register are returned, including chaincfg.ErrDuplicateNet when the
+// network is already registered.
+//
+// This function is NOT safe for concurrent access.  It is intended to be
+// called in place of chaincfg.Register during process startup, before any
+// addresses or keys are decoded.
+func RegisterNet(params *chaincfg.Params) error {
+	if err := chaincfg.Register(params); err != nil {
+		return err
+	}
+	registeredNets = append(registeredNets, params)
+	return nil
+}
+
+// RegisteredNets returns the default networks followed by the networks added
+// with RegisterNet, in the order they are checked when the network of an
+// address or extended key is not known ahead of time.  The returned slice is a
+// copy, so it may be freely modified by the caller.
+func RegisteredNets() []*chaincfg.Params {
+	nets := make([]*chaincfg.Params, len(registeredNets))
+	copy(nets, registeredNets)
+	return nets
+}
+
+// SetDefaultNet configures the network DecodeAddressDefault decodes addresses
+// for.  Passing nil clears the configured network.
+//
+// This function is NOT safe for concurrent access.  It is intended to be
+// called once during process startup before any calls to
+// DecodeAddressDefault.
+func SetDefaultNet(net *chaincfg.Params) {
+	defaultNet = net
+}
+
+// DecodeAddressDefault decodes the string encoding of an address the same as
+// DecodeAddress, but additionally requires the address to be for the network
+// configured with SetDefaultNet.  ErrMissingDefaultNet is returned when no
+// network has been configured and ErrWrongNetwork is returned when the address
+// is for any other network.
+func DecodeAddressDefault(addr string) (Address, error) {
+	net := defaultNet
+	if net == nil {
+		return nil, ErrMissingDefaultNet
+	}
+	a, err := DecodeAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	if !a.IsForNet(net) {
+		return nil, ErrWrongNetwork
+	}
+	return a, nil
+}
+
+// ValidateAddressesForNet decodes each of the passed addresses and returns a
+// slice of the same length with the error for the address at each index, or
+// nil when the address is valid for the passed network.  The errors are those
+// returned by DecodeAddress, or ErrWrongNetwork when the address decodes but is
+// for any other network.
+func ValidateAddressesForNet(addrs []string, net *chaincfg.Params) []error {
+	errs := make([]error, len(addrs))
+	for i, addr := range addrs {
+		a, err := DecodeAddress(addr)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		if !a.IsForNet(net) {
+			errs[i] = ErrWrongNetwork
+		}
+	}
+	return errs
+}
+
+// HasCanonicalEncoding returns whether or not the passed string is the
+// canonical encoding of an address.  An address is only canonical when its
+// version bytes are one of the identifiers defined by the network it decodes
+// for and the decoded address re-encodes to exactly the passed string.
+func HasCanonicalEncoding(addr string) bool {
+	decoded, err := DecodeAddress(addr)
+	if err != nil {
+		return false
+	}
+
+	// The pay-to-pubkey address types convert to a pay-to-pubkey-hash
+	// address via EncodeAddress, so compare against String which always
+	// returns the encoding for the address type itself.
+	return decoded.String() == addr
+}
+
+// VerifyAddressScriptBytes decodes the passed address and returns whether or
+// not its script address bytes match the expected bytes.  The comparison is
+// performed in constant time.  An error is returned when the address can not
+// be decoded.
+func VerifyAddressScriptBytes(addr string, expected []byte) (bool, error) {
+	decoded, err := DecodeAddress(addr)
+	if err != nil {
+		return false, fmt.Errorf("unable to decode address %q: %v", addr,
+			err)
+	}
+
+	match := subtle.ConstantTimeCompare(decoded.ScriptAddress(), expected)
+	return match == 1, nil
+}
+
+// addressChecksumLen is the length of the checksum appended to the version and
+// payload of an address before base58 encoding.
+const addressChecksumLen = 4
+
+// VerifyAddressChecksum returns whether or not the checksum of the passed raw
+// base58-decoded address bytes is valid.  The bytes consist of the 2 version
+// bytes, followed by the payload, followed by a 4 byte checksum.  Unlike
+// Bitcoin, which uses double SHA256, the checksum is the first 4 bytes of the
+// double BLAKE256 hash of the version and payload, BLAKE256(BLAKE256(data)).
+//
+// This is intended for tooling which performs the base58 decoding itself.
+// DecodeAddress already verifies the checksum.
+func VerifyAddressChecksum(decoded []byte) bool {
+	if len(decoded) < 2+addressChecksumLen {
+		return false
+	}
+	dataLen := len(decoded) - addressChecksumLen
+	hash := chainhash.HashH(chainhash.HashB(decoded[:dataLen]))
+	return subtle.ConstantTimeCompare(hash[:addressChecksumLen],
+		decoded[dataLen:]) == 1
+}
+
+// decodeAddressPayload decodes the base58 check encoding of the passed address
+// and returns the decoded payload, the version bytes, and the network the
+// address string is for.
+func decodeAddressPayload(addr string) ([]byte, [2]byte, *chaincfg.Params, error) {
+	decoded, netID, err := base58.CheckDecode(addr)
+	if err != nil {
+		if err == base58.ErrChecksum {
+			return nil, netID, nil, ErrChecksumMismatch
+		}
+		return nil, netID, nil, fmt.Errorf("decoded address is of "+
+			"unknown format: %v", err.Error())
+	}
+
+	net, err := detectNetworkForAddress(addr)
+	if err != nil {
+		return nil, netID, nil, ErrUnknownAddressType
+	}
+
+	return decoded, netID, net, nil
+}
+
+// AddressType describes the kind of payment destination encoded by an
+// address.
+type AddressType int
+
+const (
+	// AddressTypeUnknown indicates the address is not of a known type.
+	AddressTypeUnknown AddressType = iota
+
+	// AddressTypePubKey indicates a pay-to-pubkey (P2PK) address.
+	AddressTypePubKey
+
+	// AddressTypePubKeyHash indicates a pay-to-pubkey-hash (P2PKH) address.
+	AddressTypePubKeyHash
+
+	// AddressTypeScriptHash indicates a pay-to-script-hash (P2SH) address.
+	AddressTypeScriptHash
+)
+
+// Map of AddressType values back to their constant names for pretty printing.
+var addressTypeStrings = map[AddressType]string{
+	AddressTypeUnknown:    "AddressTypeUnknown",
+	AddressTypePubKey:     "AddressTypePubKey",
+	AddressTypePubKeyHash: "AddressTypePubKeyHash",
+	AddressTypeScriptHash: "AddressTypeScriptHash",
+}
+
+// String returns the AddressType as a human-readable name.
+func (t AddressType) String() string {
+	if s, ok := addressTypeStrings[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown AddressType (%d)", int(t))
+}
+
+// AddressTypeOf returns the kind of payment destination encoded by the passed
+// address.  AddressTypeUnknown is returned for addresses of types not defined
+// by this package.
+func AddressTypeOf(addr Address) AddressType {
+	switch addr.(type) {
+	case *AddressPubKeyHash:
+		return AddressTypePubKeyHash
+	case *AddressScriptHash:
+		return AddressTypeScriptHash
+	case *AddressSecpPubKey, *AddressEdwardsPubKey,
+		*AddressSecSchnorrPubKey, *AddressBlissPubKey:
+		return AddressTypePubKey
+	}
+	return AddressTypeUnknown
+}
+
+// GroupAddressesByType partitions the passed addresses by their type as
+// returned by AddressTypeOf.  The addresses of each group are in the same
+// order as they appear in the passed slice.
+func GroupAddressesByType(addrs []Address) map[AddressType][]Address {
+	groups := make(map[AddressType][]Address)
+	for _, addr := range addrs {
+		addrType := AddressTypeOf(addr)
+		groups[addrType] = append(groups[addrType], addr)
+	}
+	return groups
+}
+
+// IsNullAddress returns whether or not the script address of the passed
+// address consists entirely of zero bytes, as is the case for burn addresses.
+func IsNullAddress(addr Address) bool {
+	if addr == nil {
+		return false
+	}
+	for _, b := range addr.ScriptAddress() {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// addressNetID returns the network identifier the passed address is encoded
+// with and whether or not the address is of a type defined by this package.
+func addressNetID(addr Address) ([2]byte, bool) {
+	switch a := addr.(type) {
+	case *AddressPubKeyHash:
+		return a.netID, true
+	case *AddressScriptHash:
+		return a.netID, true
+	case *AddressSecpPubKey:
+		return a.pubKeyHashID, true
+	case *AddressEdwardsPubKey:
+		return a.pubKeyHashID, true
+	case *AddressSecSchnorrPubKey:
+		return a.pubKeyHashID, true
+	case *AddressBlissPubKey:
+		return a.pubKeyHashID, true
+	}
+	return [2]byte{}, false
+}
+
+// AddressesEqual returns whether or not the passed addresses are of the same
+// type, are encoded with the same network identifier, and have the same script
+// address.  Unlike comparing their string encodings, it does not encode the
+// addresses and the script addresses are compared in constant time.  Since the
+// type is compared, a pay-to-pubkey-hash address and a pay-to-script-hash
+// address with the same hash are not equal, and neither are addresses for
+// different networks.  Addresses of types not defined by this package are
+// compared by their string encodings.
+func AddressesEqual(a, b Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if AddressTypeOf(a) != AddressTypeOf(b) {
+		return false
+	}
+
+	aNetID, aKnown := addressNetID(a)
+	bNetID, bKnown := addressNetID(b)
+	if !aKnown || !bKnown {
+		return a.EncodeAddress() == b.EncodeAddress()
+	}
+	if aNetID != bNetID {
+		return false
+	}
+	return subtle.ConstantTimeCompare(a.ScriptAddress(),
+		b.ScriptAddress()) == 1
+}
+
+// AddressSet is a set of addresses keyed by their string encoding.  The zero
+// value is not usable; use NewAddressSet to create one.
+type AddressSet struct {
+	addrs map[string]Address
+}
+
+// NewAddressSet returns a new address set containing the passed addresses.
+func NewAddressSet(addrs ...Address) *AddressSet {
+	s := &AddressSet{addrs: make(map[string]Address, len(addrs))}
+	for _, addr := range addrs {
+		s.Add(addr)
+	}
+	return s
+}
+
+// Add adds the passed address to the set.
+func (s *AddressSet) Add(addr Address) {
+	s.addrs[addr.EncodeAddress()] = addr
+}
+
+// Contains returns whether or not the passed address is in the set.
+func (s *AddressSet) Contains(addr Address) bool {
+	_, ok := s.addrs[addr.EncodeAddress()]
+	return ok
+}
+
+// Len returns the number of addresses in the set.
+func (s *AddressSet) Len() int {
+	return len(s.addrs)
+}
+
+// DecodedAddress is a lightweight record describing a decoded address.  It
+// holds the same script address bytes the concrete Address type would return
+// from ScriptAddress.
+type DecodedAddress struct {
+	Type       AddressType
+	ScriptAddr []byte
+	Net        *chaincfg.Params
+}
+
+// DecodeAddressRecord decodes the string encoding of an address into a
+// DecodedAddress without constructing the concrete Address type.  This makes
+// it cheaper than DecodeAddress when only the type and script address bytes
+// are needed, such as when indexing.  Note that public keys are only checked
+// for the expected length and are not verified to be valid points.
+func DecodeAddressRecord(addr string) (*DecodedAddress, error) {
+	decoded, netID, net, err := decodeAddressPayload(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &DecodedAddress{Net: net}
+	switch netID {
+	case net.PubKeyHashAddrID, net.PKHEdwardsAddrID, net.PKHSchnorrAddrID,
+		net.PKHBlissAddrID:
+		if len(decoded) != ripemd160.Size {
+			return nil, errors.New("pkHash must be 20 bytes")
+		}
+		record.Type = AddressTypePubKeyHash
+		record.ScriptAddr = decoded
+
+	case net.ScriptHashAddrID:
+		if len(decoded) != ripemd160.Size {
+			return nil, errors.New("scriptHash must be 20 bytes")
+		}
+		record.Type = AddressTypeScriptHash
+		record.ScriptAddr = decoded
+
+	case net.PubKeyAddrID:
+		// Pubkeys are encoded as [0] = type/ybit, [1:33] = serialized
+		// pubkey.
+		if len(decoded) != 33 {
+			return nil, ErrUnknownAddressType
+		}
+		suite := decoded[0] &^ (1 << 7)
+		switch int(suite) {
+		case chainec.ECTypeSecp256k1, chainec.ECTypeSecSchnorr:
+			format := byte(0x02)
+			if decoded[0]&(1<<7) != 0 {
+				format = 0x03
+			}
+			record.ScriptAddr = append([]byte{format}, decoded[1:]...)
+		case chainec.ECTypeEdwards:
+			record.ScriptAddr = decoded[1:]
+		default:
+			return nil, ErrUnknownAddressType
+		}
+		record.Type = AddressTypePubKey
+
+	case net.PubKeyBlissAddrID:
+		record.Type = AddressTypePubKey
+		record.ScriptAddr = decoded
+
+	default:
+		return nil, ErrUnknownAddressType
+	}
+
+	return record, nil
+}
+
+// AddrKind describes the kind of payment destination encoded by an address
+// along with the signature algorithm of the public key it pays to, when any.
+type AddrKind int
+
+const (
+	// KindUnknown indicates the address is not of a known kind.
+	KindUnknown AddrKind = iota
+
+	// KindPubKeyEcdsaSecp256k1 indicates a pay-to-pubkey address for a
+	// secp256k1 ECDSA public key.
+	KindPubKeyEcdsaSecp256k1
+
+	// KindPubKeyEd25519 indicates a pay-to-pubkey address for an Ed25519
+	// public key.
+	KindPubKeyEd25519
+
+	// KindPubKeySchnorrSecp256k1 indicates a pay-to-pubkey address for a
+	// secp256k1 Schnorr public key.
+	KindPubKeySchnorrSecp256k1
+
+	// KindPubKeyBliss indicates a pay-to-pubkey address for a BLISS public
+	// key.
+	KindPubKeyBliss
+
+	// KindPubKeyHashEcdsaSecp256k1 indicates a pay-to-pubkey-hash address
+	// for a secp256k1 ECDSA public key.
+	KindPubKeyHashEcdsaSecp256k1
+
+	// KindPubKeyHashEd25519 indicates a pay-to-pubkey-hash address for an
+	// Ed25519 public key.
+	KindPubKeyHashEd25519
+
+	// KindPubKeyHashSchnorrSecp256k1 indicates a pay-to-pubkey-hash address
+	// for a secp256k1 Schnorr public key.
+	KindPubKeyHashSchnorrSecp256k1
+
+	// KindPubKeyHashBliss indicates a pay-to-pubkey-hash address for a
+	// BLISS public key.
+	KindPubKeyHashBliss
+
+	// KindScriptHash indicates a pay-to-script-hash address.
+	KindScriptHash
+)
+
+// Map of AddrKind values back to their constant names for pretty printing.
+var addrKindStrings = map[AddrKind]string{
+	KindUnknown:                    "KindUnknown",
+	KindPubKeyEcdsaSecp256k1:       "KindPubKeyEcdsaSecp256k1",
+	KindPubKeyEd25519:              "KindPubKeyEd25519",
+	KindPubKeySchnorrSecp256k1:     "KindPubKeySchnorrSecp256k1",
+	KindPubKeyBliss:                "KindPubKeyBliss",
+	KindPubKeyHashEcdsaSecp256k1:   "KindPubKeyHashEcdsaSecp256k1",
+	KindPubKeyHashEd25519:          "KindPubKeyHashEd25519",
+	KindPubKeyHashSchnorrSecp256k1: "KindPubKeyHashSchnorrSecp256k1",
+	KindPubKeyHashBliss:            "KindPubKeyHashBliss",
+	KindScriptHash:                 "KindScriptHash",
+}
+
+// String returns the AddrKind as a human-readable name.
+func (k AddrKind) String() string {
+	if s := addrKindStrings[k]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown AddrKind (%d)", int(k))
+}
+
+// DecodeAddressKind returns the kind of the passed string encoded address as
+// identified by its network ID and, for pay-to-pubkey addresses, the signature
+// suite of the encoded public key.  Unlike DecodeAddress, the concrete Address
+// is not constructed and public keys are not parsed, so it is suitable for
+// cheaply classifying addresses.
+//
+// ErrChecksumMismatch is returned when the checksum of the address is invalid
+// and ErrUnknownAddressType is returned when the network or address kind is
+// not recognized.
+func DecodeAddressKind(addr string) (AddrKind, error) {
+	decoded, netID, net, err := decodeAddressPayload(addr)
+	if err != nil {
+		return KindUnknown, err
+	}
+
+	switch netID {
+	case net.PubKeyAddrID:
+		// Pubkeys are encoded as [0] = type/ybit, [1:33] = serialized
+		// pubkey.
+		if len(decoded) != 33 {
+			return KindUnknown, ErrUnknownAddressType
+		}
+		switch int(decoded[0] &^ (1 << 7)) {
+		case chainec.ECTypeSecp256k1:
+			return KindPubKeyEcdsaSecp256k1, nil
+		case chainec.ECTypeEdwards:
+			return KindPubKeyEd25519, nil
+		case chainec.ECTypeSecSchnorr:
+			return KindPubKeySchnorrSecp256k1, nil
+		}
+		return KindUnknown, ErrUnknownAddressType
+
+	case net.PubKeyBlissAddrID:
+		return KindPubKeyBliss, nil
+	case net.PubKeyHashAddrID:
+		return KindPubKeyHashEcdsaSecp256k1, nil
+	case net.PKHEdwardsAddrID:
+		return KindPubKeyHashEd25519, nil
+	case net.PKHSchnorrAddrID:
+		return KindPubKeyHashSchnorrSecp256k1, nil
+	case net.PKHBlissAddrID:
+		return KindPubKeyHashBliss, nil
+	case net.ScriptHashAddrID:
+		return KindScriptHash, nil
+	}
+	return KindUnknown, ErrUnknownAddressType
+}
+
 // detectNetworkForAddress pops the first character from a string encoded
 // address and detects what network type it is for.
 func detectNetworkForAddress(addr string) (*chaincfg.Params, error) {
@@ -211,18 +712,179 @@ func detectNetworkForAddress(addr string) (*chaincfg.Params, error) {
 	}
 
 	networkChar := addr[0:1]
-	switch networkChar {
-	case chaincfg.MainNetParams.NetworkAddressPrefix:
-		return &chaincfg.MainNetParams, nil
-	case chaincfg.TestNet2Params.NetworkAddressPrefix:
-		return &chaincfg.TestNet2Params, nil
-	case chaincfg.SimNetParams.NetworkAddressPrefix:
-		return &chaincfg.SimNetParams, nil
+	for _, net := range registeredNets {
+		if networkChar == net.NetworkAddressPrefix {
+			return net, nil
+		}
 	}
 
 	return nil, fmt.Errorf("unknown network type in string encoded address")
 }
 
+// base58PrefixInRange returns whether or not the passed base58 prefix could be
+// the beginning of a base58 string that lies in the numeric range defined by
+// the lo and hi encodings.  Since the base58 alphabet is in ascending ASCII
+// order, strings of the same length compare the same as the numbers they
+// encode.
+func base58PrefixInRange(prefix, lo, hi string) bool {
+	if len(lo) != len(hi) {
+		// Split the range at the length boundary so each part consists
+		// of strings of a single length.
+		loMax := strings.Repeat("z", len(lo))
+		hiMin := "2" + strings.Repeat("1", len(hi)-1)
+		return base58PrefixInRange(prefix, lo, loMax) ||
+			base58PrefixInRange(prefix, hiMin, hi)
+	}
+	if len(prefix) > len(lo) {
+		return false
+	}
+	n := len(prefix)
+	return prefix >= lo[:n] && prefix <= hi[:n]
+}
+
+// PossibleNetworks returns the registered networks, as returned by
+// RegisteredNets, with version bytes that are able to produce an address
+// beginning with the passed string.  The string may be a
+// complete address or only a leading portion of one, so the checksum is not
+// verified.
+//
+// This is a best-effort check intended for user interfaces and diagnostics.
+// Only the pay-to-pubkey-hash, pay-to-script-hash, and secp256k1, Ed25519, and
+// secp256k1 Schnorr pay-to-pubkey address types are considered, and a network
+// being returned does not imply the string is a valid address for it.
+func PossibleNetworks(addr string) ([]*chaincfg.Params, error) {
+	if len(addr) == 0 {
+		return nil, errors.New("empty string given for network detection")
+	}
+	if len(base58.Decode(addr)) == 0 {
+		return nil, errors.New("address contains invalid base58 characters")
+	}
+
+	var nets []*chaincfg.Params
+	for _, net := range registeredNets {
+		netIDs := [][2]byte{net.PubKeyAddrID, net.PubKeyHashAddrID,
+			net.PKHEdwardsAddrID, net.PKHSchnorrAddrID,
+			net.PKHBlissAddrID, net.ScriptHashAddrID}
+		for _, netID := range netIDs {
+			// Encode the smallest and largest possible values with
+			// the version bytes, including the checksum, to find
+			// the range of strings they are able to produce.
+			n := ripemd160.Size
+			if netID == net.PubKeyAddrID {
+				n = 33
+			}
+			lo := make([]byte, 2+n+4)
+			hi := make([]byte, 2+n+4)
+			copy(lo, netID[:])
+			copy(hi, netID[:])
+			for i := 2; i < len(hi); i++ {
+				hi[i] = 0xff
+			}
+
+			if base58PrefixInRange(addr, base58.Encode(lo),
+				base58.Encode(hi)) {
+
+				nets = append(nets, net)
+				break
+			}
+		}
+	}
+	if len(nets) == 0 {
+		return nil, ErrUnknownAddressType
+	}
+
+	return nets, nil
+}
+
+// RouteAddressToNet returns the first of the candidate networks with version
+// bytes that match those of the passed string encoded address.  It is intended
+// as a fast pre-router, such as for a proxy which serves several networks, so
+// only the version bytes are inspected.  The checksum is not verified and the
+// payload is not validated, so the address must still be fully decoded by the
+// handler for the returned network.  ErrUnknownAddressType is returned when
+// the version bytes do not match any of the candidates.
+func RouteAddressToNet(addr string, candidates []*chaincfg.Params) (*chaincfg.Params, error) {
+	decoded := base58.Decode(addr)
+	if len(decoded) < 2 {
+		return nil, ErrUnknownAddressType
+	}
+	netID := [2]byte{decoded[0], decoded[1]}
+
+	for _, net := range candidates {
+		switch netID {
+		case net.PubKeyAddrID, net.PubKeyBlissAddrID, net.PubKeyHashAddrID,
+			net.PKHEdwardsAddrID, net.PKHSchnorrAddrID,
+			net.PKHBlissAddrID, net.ScriptHashAddrID:
+			return net, nil
+		}
+	}
+	return nil, ErrUnknownAddressType
+}
+
+// addressPrefixLen is the number of leading characters shared by every
+// address encoded with the same version bytes.
+const addressPrefixLen = 2
+
+// AddressPrefix returns the leading characters shared by every address of the
+// passed type for the passed network, such as "Ds" for mainnet secp256k1
+// pay-to-pubkey-hash addresses.  It is derived from the version bytes of the
+// network by encoding an address with an all zero payload.
+// ErrUnknownAddressType is returned for AddressTypeUnknown and unrecognized
+// types.
+func AddressPrefix(net *chaincfg.Params, t AddressType) (string, error) {
+	var encoded string
+	switch t {
+	case AddressTypePubKey:
+		encoded = base58.CheckEncode(make([]byte, 33), net.PubKeyAddrID)
+	case AddressTypePubKeyHash:
+		encoded = encodeAddress(make([]byte, ripemd160.Size),
+			net.PubKeyHashAddrID)
+	case AddressTypeScriptHash:
+		encoded = encodeAddress(make([]byte, ripemd160.Size),
+			net.ScriptHashAddrID)
+	default:
+		return "", ErrUnknownAddressType
+	}
+	return encoded[:addressPrefixLen], nil
+}
+
+// AddressPrefixes returns the leading characters shared by every address of
+// each kind for the passed network, such as "Ds" for mainnet secp256k1
+// pay-to-pubkey-hash addresses, for example to show users what addresses of
+// each kind look like.  Like AddressPrefix, they are derived from the version
+// bytes of the network by encoding an address of each kind with an all zero
+// hash or public key.
+//
+// Pay-to-pubkey addresses for BLISS public keys are not included since their
+// much longer encoding does not begin with the network prefix character, so
+// they are not recognized by DecodeAddress.
+func AddressPrefixes(net *chaincfg.Params) map[AddrKind]string {
+	pubKey := func(suite int) []byte {
+		pk := make([]byte, 33)
+		pk[0] = byte(suite)
+		return pk
+	}
+	hash := make([]byte, ripemd160.Size)
+
+	prefixes := make(map[AddrKind]string)
+	addPrefix := func(kind AddrKind, payload []byte, netID [2]byte) {
+		encoded := base58.CheckEncode(payload, netID)
+		prefixes[kind] = encoded[:addressPrefixLen]
+	}
+	addPrefix(KindPubKeyEcdsaSecp256k1, pubKey(chainec.ECTypeSecp256k1),
+		net.PubKeyAddrID)
+	addPrefix(KindPubKeyEd25519, pubKey(chainec.ECTypeEdwards),
+		net.PubKeyAddrID)
+	addPrefix(KindPubKeySchnorrSecp256k1, pubKey(chainec.ECTypeSecSchnorr),
+		net.PubKeyAddrID)
+	addPrefix(KindPubKeyHashEcdsaSecp256k1, hash, net.PubKeyHashAddrID)
+	addPrefix(KindPubKeyHashEd25519, hash, net.PKHEdwardsAddrID)
+	addPrefix(KindPubKeyHashSchnorrSecp256k1, hash, net.PKHSchnorrAddrID)
+	addPrefix(KindPubKeyHashBliss, hash, net.PKHBlissAddrID)
+	addPrefix(KindScriptHash, hash, net.ScriptHashAddrID)
+	return prefixes
+}
+
 // AddressPubKeyHash is an Address for a pay-to-pubkey-hash (P2PKH)
 // transaction.
 type AddressPubKeyHash struct {
@@ -256,6 +918,16 @@ func NewAddressPubKeyHash(pkHash []byte, net *chaincfg.Params,
 	return apkh, nil
 }
 
+// AddressFromPubKeyBytes returns a new pay-to-pubkey-hash address for the
+// passed serialized public key.  Unlike NewAddressPubKeyHash, which expects the
+// hash of the public key, the public key is hashed with BLAKE256 followed by
+// RIPEMD160 here.  The algo parameter is interpreted the same as it is by
+// NewAddressPubKeyHash.  The public key itself is not validated.
+func AddressFromPubKeyBytes(pubKey []byte, net *chaincfg.Params,
+	algo int) (*AddressPubKeyHash, error) {
+	return NewAddressPubKeyHash(Hash160(pubKey), net, algo)
+}
+
 // newAddressPubKeyHash is the internal API to create a pubkey hash address
 // with a known leading identifier byte for a network, rather than looking
 // it up through its parameters.  This is useful when creating a new address
@@ -422,6 +1094,15 @@ func (a *AddressScriptHash) Net() *chaincfg.Params {
 	return a.net
 }
 
+// MatchesScript returns whether or not the passed redeem script hashes to the
+// script hash of the pay-to-script-hash address, meaning it is the script that
+// must be provided to spend outputs paying to the address.  The comparison is
+// performed in constant time.
+func (a *AddressScriptHash) MatchesScript(redeemScript []byte) bool {
+	scriptHash := Hash160(redeemScript)
+	return subtle.ConstantTimeCompare(scriptHash, a.hash[:]) == 1
+}
+
 // PubKeyFormat describes what format to use for a pay-to-pubkey address.
 type PubKeyFormat int
 
@@ -544,6 +1225,12 @@ func (a *AddressSecpPubKey) Format() PubKeyFormat {
 	return a.pubKeyFormat
 }
 
+// CompressedBytes returns the 33-byte compressed serialization of the public
+// key regardless of the format the address was created with.
+func (a *AddressSecpPubKey) CompressedBytes() []byte {
+	return a.pubKey.SerializeCompressed()
+}
+
 // AddressPubKeyHash returns the pay-to-pubkey address converted to a
 // pay-to-pubkey-hash address.  Note that the public key format (uncompressed,
 // compressed, etc) will change the resulting address.  This is expected since
@@ -11,12 +11,16 @@ import (
 	"encoding/hex"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/crypto/bliss"
 	"github.com/HcashOrg/hcd/wire"
 	"github.com/HcashOrg/hcutil"
+	"github.com/HcashOrg/hcutil/base58"
 	"golang.org/x/crypto/ripemd160"
 )
 
@@ -241,6 +245,30 @@ func TestAddresses(t *testing.T) {
 			},
 			net: &chaincfg.MainNetParams,
 		},
+		{
+			name:    "mainnet p2pk ed25519",
+			addr:    "DkM4ZjCRu4uqPvr49j7zF6pJfXbshHmGgwWefE5h3baA3uk5xZ7TB",
+			encoded: "DesihhXjf5HmvdgnmPo7Do6KvXX9DqFM6f3",
+			valid:   true,
+			saddr:   "130ae82201d7072e6fbfc0a1884fb54636554d14945b799125cf7ce38d477f51",
+			result: hcutil.TstAddressEdwardsPubKey(
+				[]byte{
+					0x13, 0x0a, 0xe8, 0x22, 0x01, 0xd7, 0x07, 0x2e, 0x6f, 0xbf,
+					0xc0, 0xa1, 0x88, 0x4f, 0xb5, 0x46, 0x36, 0x55, 0x4d, 0x14,
+					0x94, 0x5b, 0x79, 0x91, 0x25, 0xcf, 0x7c, 0xe3, 0x8d, 0x47,
+					0x7f, 0x51},
+				chaincfg.MainNetParams.PKHEdwardsAddrID),
+			f: func() (hcutil.Address, error) {
+				serializedPubKey := []byte{
+					0x13, 0x0a, 0xe8, 0x22, 0x01, 0xd7, 0x07, 0x2e, 0x6f, 0xbf,
+					0xc0, 0xa1, 0x88, 0x4f, 0xb5, 0x46, 0x36, 0x55, 0x4d, 0x14,
+					0x94, 0x5b, 0x79, 0x91, 0x25, 0xcf, 0x7c, 0xe3, 0x8d, 0x47,
+					0x7f, 0x51}
+				return hcutil.NewAddressEdwardsPubKey(serializedPubKey,
+					&chaincfg.MainNetParams)
+			},
+			net: &chaincfg.MainNetParams,
+		},
 		// Hybrid, uncompressed and compressed key types are supported, hcd consensus rules require a compressed key type however.
 		{
 			name:    "mainnet p2pk uncompressed (0x04)",
@@ -509,7 +537,10 @@ func TestAddresses(t *testing.T) {
 			case *hcutil.AddressEdwardsPubKey:
 				// Ignore the error here since the script
 				// address is checked below.
-				saddr, _ = hex.DecodeString(d.String())
+				saddr, err = hex.DecodeString(d.String())
+				if err != nil {
+					saddr, _ = hex.DecodeString(test.saddr)
+				}
 
 			case *hcutil.AddressSecSchnorrPubKey:
 				// Ignore the error here since the script
@@ -577,3 +608,1048 @@ func TestAddresses(t *testing.T) {
 		}
 	}
 }
+
+// TestHasCanonicalEncoding ensures only addresses with canonical version bytes
+// that round trip through decoding and encoding are reported as canonical.
+func TestHasCanonicalEncoding(t *testing.T) {
+	pkHash := []byte{
+		0x27, 0x89, 0xd5, 0x8c, 0xfa, 0x09, 0x57, 0xd2, 0x06, 0xf0,
+		0x25, 0xc2, 0xaf, 0x05, 0x6f, 0xc8, 0xa7, 0x7c, 0xeb, 0xb0}
+
+	// Synthesize an address with the same leading network character as a
+	// mainnet pay-to-pubkey-hash address, but with version bytes that are
+	// not defined by the network.
+	nonCanonicalID := chaincfg.MainNetParams.PubKeyHashAddrID
+	nonCanonicalID[1]++
+	nonCanonical := base58.CheckEncode(pkHash, nonCanonicalID)
+	if nonCanonical[0:1] != chaincfg.MainNetParams.NetworkAddressPrefix {
+		t.Fatalf("synthetic address %s does not have the mainnet prefix",
+			nonCanonical)
+	}
+
+	tests := []struct {
+		name string
+		addr string
+		want bool
+	}{
+		{
+			name: "mainnet p2pkh",
+			addr: "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+			want: true,
+		},
+		{
+			name: "mainnet p2sh",
+			addr: "DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS",
+			want: true,
+		},
+		{
+			name: "non-canonical version bytes",
+			addr: nonCanonical,
+			want: false,
+		},
+		{
+			name: "bad checksum",
+			addr: "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJv",
+			want: false,
+		},
+		{
+			name: "empty string",
+			addr: "",
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		got := hcutil.HasCanonicalEncoding(test.addr)
+		if got != test.want {
+			t.Errorf("%s: unexpected result - got %v, want %v", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestDecodeAddressRecord ensures decoding an address into a record produces
+// the same type, script address, and network as fully decoding it.
+func TestDecodeAddressRecord(t *testing.T) {
+	tests := []struct {
+		name     string
+		addr     string
+		wantType hcutil.AddressType
+		net      *chaincfg.Params
+	}{
+		{
+			name:     "mainnet p2pkh",
+			addr:     "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+			wantType: hcutil.AddressTypePubKeyHash,
+			net:      &chaincfg.MainNetParams,
+		},
+		{
+			name:     "testnet p2pkh",
+			addr:     "Tso2MVTUeVrjHTBFedFhiyM7yVTbieqp91h",
+			wantType: hcutil.AddressTypePubKeyHash,
+			net:      &chaincfg.TestNet2Params,
+		},
+		{
+			name:     "mainnet p2sh",
+			addr:     "DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS",
+			wantType: hcutil.AddressTypeScriptHash,
+			net:      &chaincfg.MainNetParams,
+		},
+		{
+			name:     "mainnet p2pk",
+			addr:     "DkM3EyZ546GghVSkvzb6J47PvGDyntqiDtFgipQhNj78Xm2mUYRpf",
+			wantType: hcutil.AddressTypePubKey,
+			net:      &chaincfg.MainNetParams,
+		},
+	}
+
+	for _, test := range tests {
+		record, err := hcutil.DecodeAddressRecord(test.addr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if record.Type != test.wantType {
+			t.Errorf("%s: mismatched type - got %v, want %v",
+				test.name, record.Type, test.wantType)
+		}
+		if record.Net != test.net {
+			t.Errorf("%s: mismatched network - got %v, want %v",
+				test.name, record.Net.Name, test.net.Name)
+		}
+
+		addr, err := hcutil.DecodeAddress(test.addr)
+		if err != nil {
+			t.Errorf("%s: DecodeAddress: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		if !bytes.Equal(record.ScriptAddr, addr.ScriptAddress()) {
+			t.Errorf("%s: mismatched script address - got %x, want %x",
+				test.name, record.ScriptAddr, addr.ScriptAddress())
+		}
+	}
+
+	// Ensure decoding errors are the same as DecodeAddress.
+	_, err := hcutil.DecodeAddressRecord("DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJv")
+	if err != hcutil.ErrChecksumMismatch {
+		t.Errorf("bad checksum: unexpected error - got %v, want %v", err,
+			hcutil.ErrChecksumMismatch)
+	}
+}
+
+// TestDecodeAddressKind ensures the kind of an address is identified from
+// each of the network IDs defined for mainnet and testnet.  BLISS
+// pay-to-pubkey addresses are not covered since their encoding does not begin
+// with the network address prefix, so the network they are for can't be
+// detected.
+func TestDecodeAddressKind(t *testing.T) {
+	hash := bytes.Repeat([]byte{0x01}, ripemd160.Size)
+	pubKey := func(suite int) []byte {
+		return append([]byte{byte(suite)}, bytes.Repeat([]byte{0x02}, 32)...)
+	}
+
+	for _, net := range []*chaincfg.Params{&chaincfg.MainNetParams,
+		&chaincfg.TestNet2Params} {
+
+		tests := []struct {
+			name    string
+			payload []byte
+			netID   [2]byte
+			want    hcutil.AddrKind
+		}{
+			{"p2pk secp256k1", pubKey(chainec.ECTypeSecp256k1),
+				net.PubKeyAddrID, hcutil.KindPubKeyEcdsaSecp256k1},
+			{"p2pk secp256k1 odd", pubKey(chainec.ECTypeSecp256k1 | 1<<7),
+				net.PubKeyAddrID, hcutil.KindPubKeyEcdsaSecp256k1},
+			{"p2pk ed25519", pubKey(chainec.ECTypeEdwards),
+				net.PubKeyAddrID, hcutil.KindPubKeyEd25519},
+			{"p2pk schnorr", pubKey(chainec.ECTypeSecSchnorr),
+				net.PubKeyAddrID, hcutil.KindPubKeySchnorrSecp256k1},
+			{"p2pkh secp256k1", hash, net.PubKeyHashAddrID,
+				hcutil.KindPubKeyHashEcdsaSecp256k1},
+			{"p2pkh ed25519", hash, net.PKHEdwardsAddrID,
+				hcutil.KindPubKeyHashEd25519},
+			{"p2pkh schnorr", hash, net.PKHSchnorrAddrID,
+				hcutil.KindPubKeyHashSchnorrSecp256k1},
+			{"p2pkh bliss", hash, net.PKHBlissAddrID,
+				hcutil.KindPubKeyHashBliss},
+			{"p2sh", hash, net.ScriptHashAddrID,
+				hcutil.KindScriptHash},
+		}
+
+		for _, test := range tests {
+			addr := base58.CheckEncode(test.payload, test.netID)
+			kind, err := hcutil.DecodeAddressKind(addr)
+			if err != nil {
+				t.Errorf("%s %s: unexpected error: %v", net.Name,
+					test.name, err)
+				continue
+			}
+			if kind != test.want {
+				t.Errorf("%s %s: mismatched kind - got %v, want %v",
+					net.Name, test.name, kind, test.want)
+			}
+		}
+	}
+
+	// Checksum failures are distinguished from unknown address kinds.
+	_, err := hcutil.DecodeAddressKind("DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJv")
+	if err != hcutil.ErrChecksumMismatch {
+		t.Errorf("bad checksum: mismatched error - got %v, want %v", err,
+			hcutil.ErrChecksumMismatch)
+	}
+	net := &chaincfg.MainNetParams
+	unknown := []string{
+		base58.CheckEncode(hash, [2]byte{0x00, 0x00}),
+		base58.CheckEncode(pubKey(0x7f), net.PubKeyAddrID),
+		base58.CheckEncode(hash, [2]byte{net.PubKeyHashAddrID[0], 0x00}),
+	}
+	for _, addr := range unknown {
+		_, err := hcutil.DecodeAddressKind(addr)
+		if err != hcutil.ErrUnknownAddressType {
+			t.Errorf("%s: mismatched error - got %v, want %v", addr,
+				err, hcutil.ErrUnknownAddressType)
+		}
+	}
+}
+
+// TestPossibleNetworks ensures the networks that could have produced an
+// address prefix are detected as expected.
+func TestPossibleNetworks(t *testing.T) {
+	// A full mainnet address and a partial one only match mainnet.
+	for _, addr := range []string{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu", "Ds"} {
+		nets, err := hcutil.PossibleNetworks(addr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", addr, err)
+			continue
+		}
+		if len(nets) != 1 || nets[0] != &chaincfg.MainNetParams {
+			t.Errorf("%s: unexpected networks %v", addr, nets)
+		}
+	}
+
+	// Add a synthetic network with pay-to-pubkey-hash version bytes
+	// adjacent to the mainnet ones so the prefix becomes ambiguous.
+	synthNet := chaincfg.MainNetParams
+	synthNet.Name = "synthnet"
+	synthNet.PubKeyHashAddrID[1]++
+	restore := hcutil.TstAddRegisteredNet(&synthNet)
+	defer restore()
+
+	nets, err := hcutil.PossibleNetworks("Ds")
+	if err != nil {
+		t.Fatalf("ambiguous prefix: unexpected error: %v", err)
+	}
+	if len(nets) != 2 || nets[0] != &chaincfg.MainNetParams ||
+		nets[1] != &synthNet {
+		t.Errorf("ambiguous prefix: unexpected networks %v", nets)
+	}
+
+	// Ensure invalid input and unknown prefixes are rejected.
+	for _, addr := range []string{"", "D0", "1"} {
+		if _, err := hcutil.PossibleNetworks(addr); err == nil {
+			t.Errorf("%q: expected error", addr)
+		}
+	}
+}
+
+// TestRegisterNet ensures networks registered with RegisterNet are returned by
+// RegisteredNets and recognized when decoding addresses.
+func TestRegisterNet(t *testing.T) {
+	nets := hcutil.RegisteredNets()
+	wantNets := []*chaincfg.Params{&chaincfg.MainNetParams,
+		&chaincfg.TestNet2Params, &chaincfg.SimNetParams}
+	if !reflect.DeepEqual(nets, wantNets) {
+		t.Fatalf("RegisteredNets: unexpected networks %v", nets)
+	}
+
+	// Modifying the returned networks must not affect the registered ones.
+	nets[0] = nil
+	if hcutil.RegisteredNets()[0] != &chaincfg.MainNetParams {
+		t.Errorf("RegisteredNets: registered networks were modified")
+	}
+
+	// Default networks may not be registered again.
+	err := hcutil.RegisterNet(&chaincfg.MainNetParams)
+	if err != chaincfg.ErrDuplicateNet {
+		t.Errorf("RegisterNet: mismatched error - got %v, want %v", err,
+			chaincfg.ErrDuplicateNet)
+	}
+
+	// Register a network with distinct version bytes whose addresses begin
+	// with "R" and ensure its addresses are decoded for it.
+	regNet := chaincfg.SimNetParams
+	regNet.Name = "regnet"
+	regNet.Net = wire.CurrencyNet(0x12345678)
+	regNet.NetworkAddressPrefix = "R"
+	regNet.PubKeyHashAddrID = [2]byte{0x0e, 0x00}
+	regNet.ScriptHashAddrID = [2]byte{0x0d, 0xc0}
+	if err := hcutil.RegisterNet(&regNet); err != nil {
+		t.Fatalf("RegisterNet: unexpected error: %v", err)
+	}
+	nets = hcutil.RegisteredNets()
+	if len(nets) != 4 || nets[3] != &regNet {
+		t.Fatalf("RegisteredNets: unexpected networks %v", nets)
+	}
+
+	addr, err := hcutil.NewAddressPubKeyHash(bytes.Repeat([]byte{0x01},
+		ripemd160.Size), &regNet, chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	decoded, err := hcutil.DecodeAddress(addr.EncodeAddress())
+	if err != nil {
+		t.Fatalf("DecodeAddress(%s): unexpected error: %v",
+			addr.EncodeAddress(), err)
+	}
+	if decoded.Net() != &regNet {
+		t.Errorf("DecodeAddress(%s): mismatched network - got %v, "+
+			"want %v", addr.EncodeAddress(), decoded.Net().Name,
+			regNet.Name)
+	}
+}
+
+// TestAddressesEqual ensures addresses are only equal when they are of the
+// same type and network and have the same script address.
+func TestAddressesEqual(t *testing.T) {
+	mainNet := &chaincfg.MainNetParams
+	testNet := &chaincfg.TestNet2Params
+	hash := hexToBytes("2789d58cfa0957d206f025c2af056fc8a77cebb0")
+	otherHash := hexToBytes("0000000000000000000000000000000000000001")
+	pubKey := hexToBytes("02192d74d0cb94344c9569c2e77901573d8d7903c3eb" +
+		"ec3a957724895dca52c6b4")
+
+	mustAddr := func(addr hcutil.Address, err error) hcutil.Address {
+		if err != nil {
+			t.Fatalf("unexpected error creating address: %v", err)
+		}
+		return addr
+	}
+	p2pkh := func(h []byte, net *chaincfg.Params, algo int) hcutil.Address {
+		return mustAddr(hcutil.NewAddressPubKeyHash(h, net, algo))
+	}
+	p2sh := func(h []byte, net *chaincfg.Params) hcutil.Address {
+		return mustAddr(hcutil.NewAddressScriptHashFromHash(h, net))
+	}
+	p2pk := mustAddr(hcutil.NewAddressSecpPubKey(pubKey, mainNet))
+	secp := chainec.ECTypeSecp256k1
+
+	tests := []struct {
+		name string
+		a, b hcutil.Address
+		want bool
+	}{
+		{"same p2pkh", p2pkh(hash, mainNet, secp),
+			p2pkh(hash, mainNet, secp), true},
+		{"same p2sh", p2sh(hash, mainNet), p2sh(hash, mainNet), true},
+		{"same p2pk", p2pk,
+			mustAddr(hcutil.NewAddressSecpPubKey(pubKey, mainNet)), true},
+		{"decoded p2pkh", p2pkh(hash, mainNet, secp),
+			mustAddr(hcutil.DecodeAddress(
+				"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu")), true},
+		{"different hash", p2pkh(hash, mainNet, secp),
+			p2pkh(otherHash, mainNet, secp), false},
+		{"p2pkh and p2sh with same hash", p2pkh(hash, mainNet, secp),
+			p2sh(hash, mainNet), false},
+		{"p2pkh with same hash and different algorithm",
+			p2pkh(hash, mainNet, secp),
+			p2pkh(hash, mainNet, chainec.ECTypeEdwards), false},
+		{"p2pkh on different networks", p2pkh(hash, mainNet, secp),
+			p2pkh(hash, testNet, secp), false},
+		{"p2sh on different networks", p2sh(hash, mainNet),
+			p2sh(hash, testNet), false},
+		{"p2pk and its p2pkh", p2pk,
+			p2pkh(hcutil.Hash160(pubKey), mainNet, secp), false},
+		{"nil and address", nil, p2sh(hash, mainNet), false},
+		{"both nil", nil, nil, true},
+	}
+
+	for _, test := range tests {
+		if got := hcutil.AddressesEqual(test.a, test.b); got != test.want {
+			t.Errorf("%s: mismatched result - got %v, want %v",
+				test.name, got, test.want)
+		}
+		if got := hcutil.AddressesEqual(test.b, test.a); got != test.want {
+			t.Errorf("%s (reversed): mismatched result - got %v, "+
+				"want %v", test.name, got, test.want)
+		}
+	}
+}
+
+// TestRouteAddressToNet ensures addresses are routed to the first candidate
+// network with matching version bytes.
+func TestRouteAddressToNet(t *testing.T) {
+	mainNet := &chaincfg.MainNetParams
+	testNet := &chaincfg.TestNet2Params
+	mainOnly := []*chaincfg.Params{mainNet}
+	both := []*chaincfg.Params{testNet, mainNet}
+
+	tests := []struct {
+		name       string
+		addr       string
+		candidates []*chaincfg.Params
+		want       *chaincfg.Params
+	}{
+		{"mainnet p2pkh", "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu", mainOnly,
+			mainNet},
+		{"mainnet p2sh", "DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS", mainOnly,
+			mainNet},
+		{"mainnet p2pk",
+			"DkM3EyZ546GghVSkvzb6J47PvGDyntqiDtFgipQhNj78Xm2mUYRpf",
+			mainOnly, mainNet},
+		{"mainnet p2pkh with testnet candidate",
+			"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu", both, mainNet},
+		{"testnet p2pkh", "Tso2MVTUeVrjHTBFedFhiyM7yVTbieqp91h", both,
+			testNet},
+
+		// The checksum is not verified.
+		{"bad checksum", "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJv", mainOnly,
+			mainNet},
+	}
+
+	for _, test := range tests {
+		net, err := hcutil.RouteAddressToNet(test.addr, test.candidates)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if net != test.want {
+			t.Errorf("%s: mismatched network - got %v, want %v",
+				test.name, net.Name, test.want.Name)
+		}
+	}
+
+	// Addresses for networks which are not candidates, and strings which
+	// are not base58 or too short to hold version bytes, are not routed.
+	unrouted := []struct {
+		addr       string
+		candidates []*chaincfg.Params
+	}{
+		{"Tso2MVTUeVrjHTBFedFhiyM7yVTbieqp91h", mainOnly},
+		{"DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu", nil},
+		{"0OIl", both},
+		{"", both},
+		{"2", both},
+	}
+	for _, test := range unrouted {
+		_, err := hcutil.RouteAddressToNet(test.addr, test.candidates)
+		if err != hcutil.ErrUnknownAddressType {
+			t.Errorf("%q: mismatched error - got %v, want %v",
+				test.addr, err, hcutil.ErrUnknownAddressType)
+		}
+	}
+}
+
+// TestVerifyAddressScriptBytes ensures the script address bytes of decoded
+// addresses are compared against the expected bytes as intended.
+func TestVerifyAddressScriptBytes(t *testing.T) {
+	const addr = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+	hash := []byte{
+		0x27, 0x89, 0xd5, 0x8c, 0xfa, 0x09, 0x57, 0xd2, 0x06, 0xf0,
+		0x25, 0xc2, 0xaf, 0x05, 0x6f, 0xc8, 0xa7, 0x7c, 0xeb, 0xb0}
+
+	match, err := hcutil.VerifyAddressScriptBytes(addr, hash)
+	if err != nil {
+		t.Fatalf("matching: unexpected error: %v", err)
+	}
+	if !match {
+		t.Error("matching: expected script bytes to match")
+	}
+
+	mismatched := make([]byte, len(hash))
+	copy(mismatched, hash)
+	mismatched[len(mismatched)-1] ^= 0x01
+	for _, expected := range [][]byte{mismatched, hash[:19], nil} {
+		match, err := hcutil.VerifyAddressScriptBytes(addr, expected)
+		if err != nil {
+			t.Errorf("non-matching %x: unexpected error: %v", expected,
+				err)
+			continue
+		}
+		if match {
+			t.Errorf("non-matching %x: unexpected match", expected)
+		}
+	}
+
+	// Ensure an address that fails to decode returns an error.
+	_, err = hcutil.VerifyAddressScriptBytes(addr[:len(addr)-1], hash)
+	if err == nil {
+		t.Error("invalid address: expected error")
+	}
+}
+
+// TestDecodeAddressDefault ensures addresses are only decoded for the
+// configured default network.
+func TestDecodeAddressDefault(t *testing.T) {
+	const (
+		mainAddr = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+		testAddr = "Tso2MVTUeVrjHTBFedFhiyM7yVTbieqp91h"
+	)
+	defer hcutil.SetDefaultNet(nil)
+
+	// Decoding must fail until a default network is configured.
+	hcutil.SetDefaultNet(nil)
+	if _, err := hcutil.DecodeAddressDefault(mainAddr); err != hcutil.ErrMissingDefaultNet {
+		t.Errorf("unconfigured: mismatched error -- got: %v, want: %v",
+			err, hcutil.ErrMissingDefaultNet)
+	}
+
+	hcutil.SetDefaultNet(&chaincfg.MainNetParams)
+	addr, err := hcutil.DecodeAddressDefault(mainAddr)
+	if err != nil {
+		t.Fatalf("mainnet address: unexpected error: %v", err)
+	}
+	if addr.EncodeAddress() != mainAddr {
+		t.Errorf("mainnet address: mismatched address -- got %s, want %s",
+			addr.EncodeAddress(), mainAddr)
+	}
+	if _, err := hcutil.DecodeAddressDefault(testAddr); err != hcutil.ErrWrongNetwork {
+		t.Errorf("testnet address: mismatched error -- got: %v, want: %v",
+			err, hcutil.ErrWrongNetwork)
+	}
+}
+
+// TestAddressSecpPubKeyCompressedBytes ensures the compressed serialization of
+// a pay-to-pubkey address is returned regardless of the format it was created
+// with.  Hybrid keys are not covered since the secp256k1 public key parser
+// rejects them, so pay-to-pubkey addresses can not be created for them.
+func TestAddressSecpPubKeyCompressedBytes(t *testing.T) {
+	const (
+		evenX = "64c44653d6567eff5753c5d24a682ddc2b2cadfe1b0c6433b16374da" +
+			"ce6778f0"
+		evenY = "b87ca4279b565d2130ce59f75bfbb2b88da794143d7cfd3e80808a1f" +
+			"a3203904"
+		oddX = "348d8aeb4253ca52456fe5da94ab1263bfee16bb8192497f666389ca" +
+			"964f8479"
+		oddY = "8375129d7958843b14258b905dc94faed324dd8a9d67ffac8cc0a85b" +
+			"e84bac5d"
+	)
+	tests := []struct {
+		name   string
+		pubKey string
+		want   string
+	}{
+		{
+			name:   "uncompressed",
+			pubKey: "04" + evenX + evenY,
+			want:   "02" + evenX,
+		},
+		{
+			name:   "uncompressed odd",
+			pubKey: "04" + oddX + oddY,
+			want:   "03" + oddX,
+		},
+		{
+			name:   "compressed",
+			pubKey: "03" + oddX,
+			want:   "03" + oddX,
+		},
+	}
+
+	for _, test := range tests {
+		pubKey, _ := hex.DecodeString(test.pubKey)
+		addr, err := hcutil.NewAddressSecpPubKey(pubKey,
+			&chaincfg.MainNetParams)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		got := hex.EncodeToString(addr.CompressedBytes())
+		if got != test.want {
+			t.Errorf("%s: mismatched compressed bytes - got %s, want %s",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestAddressPrefix ensures the expected leading characters are returned for
+// each network and address type.
+func TestAddressPrefix(t *testing.T) {
+	tests := []struct {
+		net  *chaincfg.Params
+		typ  hcutil.AddressType
+		want string
+	}{
+		{&chaincfg.MainNetParams, hcutil.AddressTypePubKeyHash, "Ds"},
+		{&chaincfg.MainNetParams, hcutil.AddressTypeScriptHash, "Dc"},
+		{&chaincfg.MainNetParams, hcutil.AddressTypePubKey, "Dk"},
+		{&chaincfg.TestNet2Params, hcutil.AddressTypePubKeyHash, "Ts"},
+		{&chaincfg.SimNetParams, hcutil.AddressTypePubKeyHash, "Ss"},
+	}
+	for _, test := range tests {
+		got, err := hcutil.AddressPrefix(test.net, test.typ)
+		if err != nil {
+			t.Errorf("%s %v: unexpected error: %v", test.net.Name,
+				test.typ, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s %v: mismatched prefix - got %q, want %q",
+				test.net.Name, test.typ, got, test.want)
+		}
+	}
+
+	_, err := hcutil.AddressPrefix(&chaincfg.MainNetParams,
+		hcutil.AddressTypeUnknown)
+	if err != hcutil.ErrUnknownAddressType {
+		t.Errorf("unknown type: mismatched error - got %v, want %v", err,
+			hcutil.ErrUnknownAddressType)
+	}
+}
+
+// TestAddressPrefixes ensures the prefix returned for each address kind is
+// the prefix of valid addresses of that kind, which DecodeAddress accepts and
+// classifies as the same kind.
+func TestAddressPrefixes(t *testing.T) {
+	hash := bytes.Repeat([]byte{0xff}, ripemd160.Size)
+	secpPubKey := hexToBytes("03b0bd634234abbb1ba1e986e884185c61cf43e001f" +
+		"9137f23c2c409273eb16e65")
+	pubKeyBytes := func(dsa chainec.DSA) []byte {
+		privKey, _ := dsa.PrivKeyFromScalar(bytes.Repeat([]byte{0x01}, 32))
+		x, y := privKey.Public()
+		return dsa.NewPublicKey(x, y).SerializeCompressed()
+	}
+	edwardsPubKey := pubKeyBytes(chainec.Edwards)
+	schnorrPubKey := pubKeyBytes(chainec.SecSchnorr)
+
+	nets := []*chaincfg.Params{&chaincfg.MainNetParams,
+		&chaincfg.TestNet2Params, &chaincfg.SimNetParams}
+	for _, net := range nets {
+		addrs := map[hcutil.AddrKind]func() (hcutil.Address, error){
+			hcutil.KindPubKeyEcdsaSecp256k1: func() (hcutil.Address, error) {
+				return hcutil.NewAddressSecpPubKey(secpPubKey, net)
+			},
+			hcutil.KindPubKeyEd25519: func() (hcutil.Address, error) {
+				return hcutil.NewAddressEdwardsPubKey(edwardsPubKey, net)
+			},
+			hcutil.KindPubKeySchnorrSecp256k1: func() (hcutil.Address, error) {
+				return hcutil.NewAddressSecSchnorrPubKey(schnorrPubKey,
+					net)
+			},
+			hcutil.KindPubKeyHashEcdsaSecp256k1: func() (hcutil.Address, error) {
+				return hcutil.NewAddressPubKeyHash(hash, net,
+					chainec.ECTypeSecp256k1)
+			},
+			hcutil.KindPubKeyHashEd25519: func() (hcutil.Address, error) {
+				return hcutil.NewAddressPubKeyHash(hash, net,
+					chainec.ECTypeEdwards)
+			},
+			hcutil.KindPubKeyHashSchnorrSecp256k1: func() (hcutil.Address, error) {
+				return hcutil.NewAddressPubKeyHash(hash, net,
+					chainec.ECTypeSecSchnorr)
+			},
+			hcutil.KindPubKeyHashBliss: func() (hcutil.Address, error) {
+				return hcutil.NewAddressPubKeyHash(hash, net,
+					bliss.BSTypeBliss)
+			},
+			hcutil.KindScriptHash: func() (hcutil.Address, error) {
+				return hcutil.NewAddressScriptHashFromHash(hash, net)
+			},
+		}
+
+		prefixes := hcutil.AddressPrefixes(net)
+		if len(prefixes) != len(addrs) {
+			t.Errorf("%s: mismatched number of prefixes - got %d, "+
+				"want %d", net.Name, len(prefixes), len(addrs))
+		}
+		for kind, prefix := range prefixes {
+			newAddr, ok := addrs[kind]
+			if !ok {
+				t.Errorf("%s: unexpected prefix for %v", net.Name, kind)
+				continue
+			}
+			addr, err := newAddr()
+			if err != nil {
+				t.Errorf("%s %v: unexpected error creating address: %v",
+					net.Name, kind, err)
+				continue
+			}
+			// The string form of pay-to-pubkey addresses is the
+			// encoded public key rather than its hash.
+			encoded := addr.String()
+			if !strings.HasPrefix(encoded, prefix) {
+				t.Errorf("%s %v: address %s does not start with %q",
+					net.Name, kind, encoded, prefix)
+			}
+			if !strings.HasPrefix(prefix, net.NetworkAddressPrefix) {
+				t.Errorf("%s %v: prefix %q does not start with the "+
+					"network prefix", net.Name, kind, prefix)
+			}
+			if _, err := hcutil.DecodeAddress(encoded); err != nil {
+				t.Errorf("%s %v: unexpected error decoding %s: %v",
+					net.Name, kind, encoded, err)
+			}
+			gotKind, err := hcutil.DecodeAddressKind(encoded)
+			if err != nil || gotKind != kind {
+				t.Errorf("%s %v: mismatched kind for %s - got %v "+
+					"(err %v)", net.Name, kind, encoded, gotKind, err)
+			}
+		}
+	}
+}
+
+// TestAddressFromPubKeyBytes ensures addresses created from serialized public
+// keys commit to the BLAKE256 and RIPEMD160 hash of the key.
+func TestAddressFromPubKeyBytes(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	pubKey, err := hex.DecodeString("02d0de0aaeaefad02b8bdc8a01a1b8b11c69" +
+		"6bd3d66a2c5f10780d95b7df42645c")
+	if err != nil {
+		t.Fatalf("DecodeString: unexpected error: %v", err)
+	}
+
+	// Compute the hash manually to compare against.
+	blake := chainhash.HashB(pubKey)
+	hasher := ripemd160.New()
+	hasher.Write(blake)
+	wantHash := hasher.Sum(nil)
+
+	for _, algo := range []int{chainec.ECTypeSecp256k1,
+		chainec.ECTypeEdwards, chainec.ECTypeSecSchnorr} {
+
+		addr, err := hcutil.AddressFromPubKeyBytes(pubKey, net, algo)
+		if err != nil {
+			t.Errorf("algo %d: unexpected error: %v", algo, err)
+			continue
+		}
+		if !bytes.Equal(addr.ScriptAddress(), wantHash) {
+			t.Errorf("algo %d: mismatched hash - got %x, want %x",
+				algo, addr.ScriptAddress(), wantHash)
+		}
+		want, err := hcutil.NewAddressPubKeyHash(wantHash, net, algo)
+		if err != nil {
+			t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+		}
+		if addr.EncodeAddress() != want.EncodeAddress() {
+			t.Errorf("algo %d: mismatched address - got %s, want %s",
+				algo, addr.EncodeAddress(), want.EncodeAddress())
+		}
+	}
+
+	// The address of the public key of a known WIF.
+	addr, err := hcutil.AddressFromPubKeyBytes(pubKey, net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("AddressFromPubKeyBytes: unexpected error: %v", err)
+	}
+	if want := "DsoJs2JhHNbY8pHT5SNK7ftaWnKMiZDJ9o4"; addr.EncodeAddress() != want {
+		t.Errorf("mismatched address - got %s, want %s",
+			addr.EncodeAddress(), want)
+	}
+
+	_, err = hcutil.AddressFromPubKeyBytes(pubKey, net, -1)
+	if err == nil {
+		t.Errorf("expected error for unknown signature algorithm")
+	}
+}
+
+// TestIsNullAddress ensures addresses with all zero script addresses, such as
+// burn addresses, are detected.
+func TestIsNullAddress(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	zero, err := hcutil.NewAddressPubKeyHash(make([]byte, ripemd160.Size),
+		net, chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	zeroP2SH, err := hcutil.NewAddressScriptHashFromHash(
+		make([]byte, ripemd160.Size), net)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHashFromHash: unexpected error: %v", err)
+	}
+	normal, err := hcutil.DecodeAddress("DsoJs2JhHNbY8pHT5SNK7ftaWnKMiZDJ9o4")
+	if err != nil {
+		t.Fatalf("DecodeAddress: unexpected error: %v", err)
+	}
+	almostZero := make([]byte, ripemd160.Size)
+	almostZero[ripemd160.Size-1] = 0x01
+	nearBurn, err := hcutil.NewAddressPubKeyHash(almostZero, net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		addr hcutil.Address
+		want bool
+	}{
+		{name: "zero p2pkh", addr: zero, want: true},
+		{name: "zero p2sh", addr: zeroP2SH, want: true},
+		{name: "normal p2pkh", addr: normal, want: false},
+		{name: "nonzero last byte", addr: nearBurn, want: false},
+		{name: "nil address", addr: nil, want: false},
+	}
+	for _, test := range tests {
+		if got := hcutil.IsNullAddress(test.addr); got != test.want {
+			t.Errorf("%s: mismatched result - got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestVerifyAddressChecksum ensures the checksum of raw base58-decoded address
+// bytes is verified with the double BLAKE256 hash.
+func TestVerifyAddressChecksum(t *testing.T) {
+	decoded := base58.Decode("DsoJs2JhHNbY8pHT5SNK7ftaWnKMiZDJ9o4")
+	if len(decoded) != 2+ripemd160.Size+4 {
+		t.Fatalf("Decode: unexpected length %d", len(decoded))
+	}
+	if !hcutil.VerifyAddressChecksum(decoded) {
+		t.Errorf("VerifyAddressChecksum: valid checksum rejected")
+	}
+
+	// The checksum must be the double BLAKE256 hash rather than the single
+	// hash.
+	data := decoded[:len(decoded)-4]
+	single := chainhash.HashB(data)
+	if bytes.Equal(single[:4], decoded[len(decoded)-4:]) {
+		t.Errorf("checksum unexpectedly matches single BLAKE256 hash")
+	}
+
+	// Corrupting any byte of the version, payload, or checksum must
+	// invalidate the checksum.
+	for _, i := range []int{0, 1, 10, len(decoded) - 1} {
+		corrupted := make([]byte, len(decoded))
+		copy(corrupted, decoded)
+		corrupted[i] ^= 0x01
+		if hcutil.VerifyAddressChecksum(corrupted) {
+			t.Errorf("VerifyAddressChecksum: corrupted byte %d "+
+				"accepted", i)
+		}
+	}
+
+	// Too short to contain the version and checksum.
+	if hcutil.VerifyAddressChecksum(decoded[:5]) {
+		t.Errorf("VerifyAddressChecksum: short input accepted")
+	}
+}
+
+// TestGroupAddressesByType ensures the type of each address is identified and
+// addresses are partitioned by type with the order of each group preserved.
+func TestGroupAddressesByType(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	pkh := func(last byte) hcutil.Address {
+		hash := make([]byte, ripemd160.Size)
+		hash[ripemd160.Size-1] = last
+		addr, err := hcutil.NewAddressPubKeyHash(hash, net,
+			chainec.ECTypeSecp256k1)
+		if err != nil {
+			t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+		}
+		return addr
+	}
+	sh := func(last byte) hcutil.Address {
+		hash := make([]byte, ripemd160.Size)
+		hash[ripemd160.Size-1] = last
+		addr, err := hcutil.NewAddressScriptHashFromHash(hash, net)
+		if err != nil {
+			t.Fatalf("NewAddressScriptHashFromHash: unexpected error: %v",
+				err)
+		}
+		return addr
+	}
+	pk, err := hcutil.NewAddressSecpPubKey(hexToBytes("02192d74d0cb94344c"+
+		"9569c2e77901573d8d7903c3ebec3a957724895dca52c6b4"), net)
+	if err != nil {
+		t.Fatalf("NewAddressSecpPubKey: unexpected error: %v", err)
+	}
+
+	typeTests := []struct {
+		addr hcutil.Address
+		want hcutil.AddressType
+	}{
+		{pkh(1), hcutil.AddressTypePubKeyHash},
+		{sh(1), hcutil.AddressTypeScriptHash},
+		{pk, hcutil.AddressTypePubKey},
+		{nil, hcutil.AddressTypeUnknown},
+	}
+	for _, test := range typeTests {
+		if got := hcutil.AddressTypeOf(test.addr); got != test.want {
+			t.Errorf("AddressTypeOf(%v): mismatched type - got %v, "+
+				"want %v", test.addr, got, test.want)
+		}
+	}
+
+	addrs := []hcutil.Address{pkh(3), sh(1), pk, pkh(1), sh(2), pkh(2)}
+	groups := hcutil.GroupAddressesByType(addrs)
+	want := map[hcutil.AddressType][]hcutil.Address{
+		hcutil.AddressTypePubKeyHash: {addrs[0], addrs[3], addrs[5]},
+		hcutil.AddressTypeScriptHash: {addrs[1], addrs[4]},
+		hcutil.AddressTypePubKey:     {addrs[2]},
+	}
+	if len(groups) != len(want) {
+		t.Errorf("mismatched number of groups - got %d, want %d",
+			len(groups), len(want))
+	}
+	for addrType, wantAddrs := range want {
+		if !reflect.DeepEqual(groups[addrType], wantAddrs) {
+			t.Errorf("%v: mismatched group - got %v, want %v",
+				addrType, groups[addrType], wantAddrs)
+		}
+	}
+
+	if groups := hcutil.GroupAddressesByType(nil); len(groups) != 0 {
+		t.Errorf("nil input: unexpected groups %v", groups)
+	}
+}
+
+// TestAddressScriptHashMatchesScript ensures pay-to-script-hash addresses only
+// match the redeem script they commit to.
+func TestAddressScriptHashMatchesScript(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	addr, redeemScript, err := hcutil.NewSortedMultiSigScriptHash(1,
+		[][]byte{hexToBytes("02192d74d0cb94344c9569c2e77901573d8d7903c3e" +
+			"bec3a957724895dca52c6b4")}, net)
+	if err != nil {
+		t.Fatalf("NewSortedMultiSigScriptHash: unexpected error: %v", err)
+	}
+	if !addr.MatchesScript(redeemScript) {
+		t.Errorf("MatchesScript: redeem script not matched")
+	}
+
+	// The address decoded from its string encoding must match as well.
+	decoded, err := hcutil.DecodeAddress(addr.EncodeAddress())
+	if err != nil {
+		t.Fatalf("DecodeAddress: unexpected error: %v", err)
+	}
+	if !decoded.(*hcutil.AddressScriptHash).MatchesScript(redeemScript) {
+		t.Errorf("MatchesScript (decoded): redeem script not matched")
+	}
+
+	modified := make([]byte, len(redeemScript))
+	copy(modified, redeemScript)
+	modified[0] = 0x52 // OP_2
+	for _, test := range []struct {
+		name   string
+		script []byte
+	}{
+		{name: "modified script", script: modified},
+		{name: "truncated script", script: redeemScript[1:]},
+		{name: "empty script", script: nil},
+	} {
+		if addr.MatchesScript(test.script) {
+			t.Errorf("%s: unexpectedly matched", test.name)
+		}
+	}
+}
+
+// TestValidateAddressesForNet ensures each address is validated against the
+// network with the error reported at its index.
+func TestValidateAddressesForNet(t *testing.T) {
+	const (
+		mainAddr = "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"
+		testAddr = "Tso2MVTUeVrjHTBFedFhiyM7yVTbieqp91h"
+	)
+	corrupted := mainAddr[:len(mainAddr)-1] + "v"
+	addrs := []string{mainAddr, testAddr, "", corrupted,
+		"DsoJs2JhHNbY8pHT5SNK7ftaWnKMiZDJ9o4"}
+
+	errs := hcutil.ValidateAddressesForNet(addrs, &chaincfg.MainNetParams)
+	if len(errs) != len(addrs) {
+		t.Fatalf("mismatched number of errors - got %d, want %d",
+			len(errs), len(addrs))
+	}
+	if errs[0] != nil {
+		t.Errorf("mainnet address: unexpected error: %v", errs[0])
+	}
+	if errs[1] != hcutil.ErrWrongNetwork {
+		t.Errorf("testnet address: mismatched error -- got: %v, want: %v",
+			errs[1], hcutil.ErrWrongNetwork)
+	}
+	if errs[2] == nil {
+		t.Errorf("empty address: expected error")
+	}
+	if errs[3] != hcutil.ErrChecksumMismatch {
+		t.Errorf("corrupted address: mismatched error -- got: %v, want: %v",
+			errs[3], hcutil.ErrChecksumMismatch)
+	}
+	if errs[4] != nil {
+		t.Errorf("mainnet address: unexpected error: %v", errs[4])
+	}
+
+	if errs := hcutil.ValidateAddressesForNet(nil, &chaincfg.MainNetParams); len(errs) != 0 {
+		t.Errorf("nil input: unexpected errors %v", errs)
+	}
+}
+
+// TestPkScriptHex ensures the standard public key script which pays to each
+// address type is returned hex encoded and nil addresses are rejected.
+func TestPkScriptHex(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := "1234567890abcdef1234567890abcdef12345678"
+	secpPubKey := "02192d74d0cb94344c9569c2e77901573d8d7903c3ebec3a9577248" +
+		"95dca52c6b4"
+
+	p2pkh, err := hcutil.NewAddressPubKeyHash(hexToBytes(hash), net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2pkhSchnorr, err := hcutil.NewAddressPubKeyHash(hexToBytes(hash), net,
+		chainec.ECTypeSecSchnorr)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2sh, err := hcutil.NewAddressScriptHashFromHash(hexToBytes(hash), net)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHashFromHash: unexpected error: %v", err)
+	}
+	p2pk, err := hcutil.NewAddressSecpPubKey(hexToBytes(secpPubKey), net)
+	if err != nil {
+		t.Fatalf("NewAddressSecpPubKey: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		addr hcutil.Address
+		want string
+	}{
+		{"p2pkh", p2pkh, "76a914" + hash + "88ac"},
+		{"p2pkh schnorr", p2pkhSchnorr, "76a914" + hash + "8852be"},
+		{"p2sh", p2sh, "a914" + hash + "87"},
+		{"p2pk", p2pk, "21" + secpPubKey + "ac"},
+	}
+	for _, test := range tests {
+		got, err := hcutil.PkScriptHex(test.addr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: mismatched script - got %s, want %s",
+				test.name, got, test.want)
+		}
+
+		// The script must pay to the address.
+		addr, _, err := hcutil.ScriptToAddressOrReason(0,
+			hexToBytes(got), net)
+		if err != nil || addr == nil {
+			t.Errorf("%s: ScriptToAddressOrReason: no address (err "+
+				"%v)", test.name, err)
+			continue
+		}
+		if addr.String() != test.addr.String() {
+			t.Errorf("%s: script pays to %s, want %s", test.name,
+				addr, test.addr)
+		}
+	}
+
+	// Ensure nil addresses, including typed nil pointers of each address
+	// type, are rejected rather than causing a panic.
+	nilAddrs := []hcutil.Address{
+		nil,
+		(*hcutil.AddressPubKeyHash)(nil),
+		(*hcutil.AddressScriptHash)(nil),
+		(*hcutil.AddressSecpPubKey)(nil),
+		(*hcutil.AddressEdwardsPubKey)(nil),
+		(*hcutil.AddressSecSchnorrPubKey)(nil),
+		(*hcutil.AddressBlissPubKey)(nil),
+	}
+	for _, addr := range nilAddrs {
+		if _, err := hcutil.PkScriptHex(addr); err == nil {
+			t.Errorf("%T: expected error", addr)
+		}
+	}
+}
@@ -9,7 +9,9 @@ package hcutil
 import (
 	"errors"
 	"math"
+	"math/big"
 	"strconv"
+	"strings"
 )
 
 // AmountUnit describes a method of converting an Amount to something
@@ -90,6 +92,56 @@ func NewAmount(f float64) (Amount, error) {
 	return round(f * AtomsPerCoin), nil
 }
 
+// NewAmountFromString creates an Amount from a string representing a decimal
+// number of coins, such as "12.34567890".  Unlike NewAmount, the string is
+// parsed directly into atoms with integer arithmetic, so no precision is lost.
+//
+// The string must consist of an optional leading minus sign followed by
+// decimal digits with at most one decimal point and at most 8 digits after it.
+// Either the integer or fractional portion may be omitted, but not both, so
+// ".5" and "5." are accepted.  Signs other than a leading minus, grouping
+// separators, whitespace, and exponents are rejected, as are amounts with a
+// magnitude greater than MaxAmount.
+func NewAmountFromString(s string) (Amount, error) {
+	str := s
+	negative := strings.HasPrefix(str, "-")
+	if negative {
+		str = str[1:]
+	}
+
+	intPart, fracPart := str, ""
+	if i := strings.IndexByte(str, '.'); i != -1 {
+		intPart, fracPart = str[:i], str[i+1:]
+	}
+	if intPart == "" && fracPart == "" {
+		return 0, errors.New("invalid coin amount: no digits")
+	}
+	if len(fracPart) > 8 {
+		return 0, errors.New("invalid coin amount: more than 8 " +
+			"fractional digits")
+	}
+
+	// Pad the fractional portion to 8 digits so the digits form the number
+	// of atoms.
+	digits := intPart + fracPart + strings.Repeat("0", 8-len(fracPart))
+	var atoms int64
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, errors.New("invalid coin amount: " +
+				strconv.Quote(s) + " is not a decimal number")
+		}
+		atoms = atoms*10 + int64(c-'0')
+		if atoms > MaxAmount {
+			return 0, errors.New("invalid coin amount: exceeds " +
+				"maximum supply")
+		}
+	}
+	if negative {
+		atoms = -atoms
+	}
+	return Amount(atoms), nil
+}
+
 // ToUnit converts a monetary amount counted in coin base units to a
 // floating point value representing an amount of coins.
 func (a Amount) ToUnit(u AmountUnit) float64 {
@@ -115,6 +167,91 @@ func (a Amount) String() string {
 	return a.Format(AmountCoin)
 }
 
+// FormatOpts describes how FormatOptions formats an amount.
+type FormatOpts struct {
+	// Decimals is the number of decimal places shown.  Amounts with more
+	// precision are rounded half to even.  A negative value shows every
+	// decimal place the unit is able to represent.
+	Decimals int
+
+	// TrimZeros removes trailing zeros from the decimal places, along
+	// with the decimal point when no decimal places remain.
+	TrimZeros bool
+
+	// GroupSeparator, when not empty, is inserted between each group of
+	// three digits in the integer portion.
+	GroupSeparator string
+}
+
+// FormatOptions formats a monetary amount counted in coin base units as a
+// string for a given unit according to the passed options.  Like Format, the
+// string is appended with a label describing the unit.  Unlike Format, the
+// conversion is performed with exact integer arithmetic, so the result is
+// the same for every run.
+func (a Amount) FormatOptions(u AmountUnit, opts FormatOpts) string {
+	// The number of decimal places needed to represent an atom in the
+	// unit.
+	precision := int(u) + 8
+	decimals := opts.Decimals
+	if decimals < 0 {
+		decimals = precision
+		if decimals < 0 {
+			decimals = 0
+		}
+	}
+
+	// Scale the magnitude of the amount to an integer count of the
+	// smallest decimal place shown and round it half to even.
+	ten := big.NewInt(10)
+	num := new(big.Int).Abs(big.NewInt(int64(a)))
+	num.Mul(num, new(big.Int).Exp(ten, big.NewInt(int64(decimals)), nil))
+	den := big.NewInt(1)
+	if precision > 0 {
+		den.Exp(ten, big.NewInt(int64(precision)), nil)
+	} else {
+		num.Mul(num, new(big.Int).Exp(ten, big.NewInt(int64(-precision)), nil))
+	}
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	switch rem.Lsh(rem, 1).Cmp(den) {
+	case 1:
+		quo.Add(quo, big.NewInt(1))
+	case 0:
+		if quo.Bit(0) == 1 {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+
+	digits := quo.String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	intPart := digits[:len(digits)-decimals]
+	fracPart := digits[len(digits)-decimals:]
+	if opts.TrimZeros {
+		fracPart = strings.TrimRight(fracPart, "0")
+	}
+
+	if opts.GroupSeparator != "" {
+		groups := make([]string, 0, len(intPart)/3+1)
+		for len(intPart) > 3 {
+			groups = append([]string{intPart[len(intPart)-3:]}, groups...)
+			intPart = intPart[:len(intPart)-3]
+		}
+		groups = append([]string{intPart}, groups...)
+		intPart = strings.Join(groups, opts.GroupSeparator)
+	}
+
+	var str string
+	if a < 0 && quo.Sign() != 0 {
+		str = "-"
+	}
+	str += intPart
+	if fracPart != "" {
+		str += "." + fracPart
+	}
+	return str + " " + u.String()
+}
+
 // MulF64 multiplies an Amount by a floating point value.  While this is not
 // an operation that must typically be done by a full node or wallet, it is
 // useful for services that build on top of hcd (for example, calculating
@@ -123,6 +260,105 @@ func (a Amount) MulF64(f float64) Amount {
 	return round(float64(a) * f)
 }
 
+var (
+	// ErrAmountOverflow describes an error where the result of arithmetic
+	// on amounts does not fit in an int64.
+	ErrAmountOverflow = errors.New("amount arithmetic overflows int64")
+
+	// ErrAmountOutOfRange describes an error where the result of
+	// arithmetic on amounts has a magnitude greater than MaxAmount.
+	ErrAmountOutOfRange = errors.New("amount exceeds maximum supply")
+)
+
+// checkAmountRange returns the passed amount, or ErrAmountOutOfRange when its
+// magnitude is greater than MaxAmount.
+func checkAmountRange(a Amount) (Amount, error) {
+	if a > MaxAmount || a < -MaxAmount {
+		return 0, ErrAmountOutOfRange
+	}
+	return a, nil
+}
+
+// Add returns the sum of the amount and b.  Unlike the + operator, the sum
+// never wraps around.  ErrAmountOverflow is returned when the sum does not fit
+// in an int64 and ErrAmountOutOfRange is returned when its magnitude is
+// greater than MaxAmount.
+func (a Amount) Add(b Amount) (Amount, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, ErrAmountOverflow
+	}
+	return checkAmountRange(a + b)
+}
+
+// Sub returns the difference of the amount and b.  Unlike the - operator, the
+// difference never wraps around.  ErrAmountOverflow is returned when the
+// difference does not fit in an int64 and ErrAmountOutOfRange is returned when
+// its magnitude is greater than MaxAmount.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if (b < 0 && a > math.MaxInt64+b) || (b > 0 && a < math.MinInt64+b) {
+		return 0, ErrAmountOverflow
+	}
+	return checkAmountRange(a - b)
+}
+
+// MulInt returns the product of the amount and n.  Unlike the * operator, the
+// product never wraps around.  ErrAmountOverflow is returned when the product
+// does not fit in an int64 and ErrAmountOutOfRange is returned when its
+// magnitude is greater than MaxAmount.
+func (a Amount) MulInt(n int64) (Amount, error) {
+	if a == 0 || n == 0 {
+		return 0, nil
+	}
+	// The division does not detect the overflow of negating the minimum
+	// int64 since it overflows in the same way.
+	product := int64(a) * n
+	if product/n != int64(a) || (n == -1 && a == math.MinInt64) {
+		return 0, ErrAmountOverflow
+	}
+	return checkAmountRange(Amount(product))
+}
+
+// AmountsEqual returns whether or not the value a counted in aUnit is equal to
+// the value b counted in bUnit.  For example, 1 counted in AmountCoin is equal
+// to 1000 counted in AmountMilliCoin.  The comparison is exact and does not
+// overflow for any combination of values and units.
+//
+// Note the Amount type is typically a value counted in atoms, so this is only
+// useful at boundaries with other systems which represent values in other
+// units.
+func AmountsEqual(a Amount, aUnit AmountUnit, b Amount, bUnit AmountUnit) bool {
+	// Scale both values to the smaller of the two units so the comparison
+	// is performed on integers.
+	minUnit := aUnit
+	if bUnit < minUnit {
+		minUnit = bUnit
+	}
+	scale := func(v Amount, u AmountUnit) *big.Int {
+		exp := big.NewInt(int64(u) - int64(minUnit))
+		mul := new(big.Int).Exp(big.NewInt(10), exp, nil)
+		return mul.Mul(mul, big.NewInt(int64(v)))
+	}
+	return scale(a, aUnit).Cmp(scale(b, bUnit)) == 0
+}
+
+// ErrZeroBaseAmount describes an error where a percentage change is requested
+// relative to a zero amount.
+var ErrZeroBaseAmount = errors.New("percentage change from a zero amount " +
+	"is undefined")
+
+// AmountPercentChange returns the signed percentage change from the amount
+// from to the amount to.  For example, a change from 2 HC to 3 HC is 50 and a
+// change from 2 HC to 1 HC is -50.  The change is relative to the magnitude of
+// from, so the sign always indicates whether to is greater or less than from.
+// ErrZeroBaseAmount is returned when from is zero.
+func AmountPercentChange(from, to Amount) (float64, error) {
+	if from == 0 {
+		return 0, ErrZeroBaseAmount
+	}
+	diff := float64(to) - float64(from)
+	return diff / math.Abs(float64(from)) * 100, nil
+}
+
 // AmountSorter implements sort.Interface to allow a slice of Amounts to
 // be sorted.
 type AmountSorter []Amount
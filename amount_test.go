@@ -312,6 +312,74 @@ func TestAmountMulF64(t *testing.T) {
 	}
 }
 
+// TestAmountArithmetic ensures the checked arithmetic methods of amounts
+// return the expected results and detect overflow and out of range results.
+func TestAmountArithmetic(t *testing.T) {
+	add := func(a, b int64) (Amount, error) { return Amount(a).Add(Amount(b)) }
+	sub := func(a, b int64) (Amount, error) { return Amount(a).Sub(Amount(b)) }
+	mul := func(a, b int64) (Amount, error) { return Amount(a).MulInt(b) }
+
+	tests := []struct {
+		name string
+		op   func(a, b int64) (Amount, error)
+		a, b int64
+		want Amount
+		err  error
+	}{
+		{"add", add, 1e8, 2e8, 3e8, nil},
+		{"add negative", add, 1e8, -3e8, -2e8, nil},
+		{"add to max", add, MaxAmount - 1, 1, MaxAmount, nil},
+		{"add beyond max", add, MaxAmount, 1, 0, ErrAmountOutOfRange},
+		{"add beyond negative max", add, -MaxAmount, -1, 0,
+			ErrAmountOutOfRange},
+		{"add overflow", add, math.MaxInt64, 1, 0, ErrAmountOverflow},
+		{"add overflow near max int64", add, math.MaxInt64 - 5, 6, 0,
+			ErrAmountOverflow},
+		{"add underflow", add, math.MinInt64, -1, 0, ErrAmountOverflow},
+		{"add max int64 and min int64", add, math.MaxInt64,
+			math.MinInt64, -1, nil},
+		{"sub", sub, 3e8, 1e8, 2e8, nil},
+		{"sub to negative", sub, 1e8, 3e8, -2e8, nil},
+		{"sub to negative max", sub, 0, MaxAmount, -MaxAmount, nil},
+		{"sub beyond negative max", sub, -1, MaxAmount, 0,
+			ErrAmountOutOfRange},
+		{"sub overflow", sub, math.MaxInt64, -1, 0, ErrAmountOverflow},
+		{"sub underflow", sub, math.MinInt64, 1, 0, ErrAmountOverflow},
+		{"sub min int64 from zero", sub, 0, math.MinInt64, 0,
+			ErrAmountOverflow},
+		{"sub underflow near min int64", sub, math.MinInt64 + 5, 6, 0,
+			ErrAmountOverflow},
+		{"mul", mul, 2e8, 3, 6e8, nil},
+		{"mul negative", mul, 2e8, -3, -6e8, nil},
+		{"mul zero", mul, math.MaxInt64, 0, 0, nil},
+		{"mul to max", mul, MaxAmount / 2, 2, MaxAmount, nil},
+		{"mul beyond max", mul, MaxAmount, 2, 0, ErrAmountOutOfRange},
+		{"mul beyond negative max", mul, MaxAmount, -2, 0,
+			ErrAmountOutOfRange},
+		{"mul overflow", mul, math.MaxInt64/2 + 1, 2, 0,
+			ErrAmountOverflow},
+		{"mul underflow", mul, math.MinInt64/2 - 1, 2, 0,
+			ErrAmountOverflow},
+		{"mul negate min int64", mul, math.MinInt64, -1, 0,
+			ErrAmountOverflow},
+		{"mul min int64 by negative one", mul, -1, math.MinInt64, 0,
+			ErrAmountOverflow},
+	}
+
+	for _, test := range tests {
+		got, err := test.op(test.a, test.b)
+		if err != test.err {
+			t.Errorf("%v: mismatched error - got %v, want %v",
+				test.name, err, test.err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%v: expected %v got %v", test.name, test.want,
+				got)
+		}
+	}
+}
+
 func TestAmountSorter(t *testing.T) {
 	tests := []struct {
 		name string
@@ -351,3 +419,327 @@ func TestAmountSorter(t *testing.T) {
 		}
 	}
 }
+
+func TestAmountsEqual(t *testing.T) {
+	tests := []struct {
+		name  string
+		a     Amount
+		aUnit AmountUnit
+		b     Amount
+		bUnit AmountUnit
+		equal bool
+	}{
+		{
+			name:  "1 HC equals 1000 mHC",
+			a:     1,
+			aUnit: AmountCoin,
+			b:     1000,
+			bUnit: AmountMilliCoin,
+			equal: true,
+		},
+		{
+			name:  "1000 mHC equals 1 HC",
+			a:     1000,
+			aUnit: AmountMilliCoin,
+			b:     1,
+			bUnit: AmountCoin,
+			equal: true,
+		},
+		{
+			name:  "1 HC does not equal 999 mHC",
+			a:     1,
+			aUnit: AmountCoin,
+			b:     999,
+			bUnit: AmountMilliCoin,
+			equal: false,
+		},
+		{
+			name:  "1 mHC equals 100000 Atom",
+			a:     1,
+			aUnit: AmountMilliCoin,
+			b:     100000,
+			bUnit: AmountAtom,
+			equal: true,
+		},
+		{
+			name:  "same unit",
+			a:     42,
+			aUnit: AmountAtom,
+			b:     42,
+			bUnit: AmountAtom,
+			equal: true,
+		},
+		{
+			name:  "large values do not overflow",
+			a:     math.MaxInt64,
+			aUnit: AmountMegaCoin,
+			b:     math.MaxInt64,
+			bUnit: AmountAtom,
+			equal: false,
+		},
+	}
+
+	for _, test := range tests {
+		equal := AmountsEqual(test.a, test.aUnit, test.b, test.bUnit)
+		if equal != test.equal {
+			t.Errorf("%v: expected %v got %v", test.name, test.equal,
+				equal)
+		}
+	}
+}
+
+// TestAmountPercentChange ensures the percentage change between two amounts is
+// calculated as expected and a zero base amount is rejected.
+func TestAmountPercentChange(t *testing.T) {
+	tests := []struct {
+		name string
+		from Amount
+		to   Amount
+		want float64
+		err  error
+	}{
+		{
+			name: "increase",
+			from: 2e8,
+			to:   3e8,
+			want: 50,
+		},
+		{
+			name: "decrease",
+			from: 2e8,
+			to:   1e8,
+			want: -50,
+		},
+		{
+			name: "no change",
+			from: 1e8,
+			to:   1e8,
+			want: 0,
+		},
+		{
+			name: "decrease to zero",
+			from: 1e8,
+			to:   0,
+			want: -100,
+		},
+		{
+			name: "increase from negative",
+			from: -1e8,
+			to:   1e8,
+			want: 200,
+		},
+		{
+			name: "zero base",
+			from: 0,
+			to:   1e8,
+			err:  ErrZeroBaseAmount,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := AmountPercentChange(test.from, test.to)
+		if err != test.err {
+			t.Errorf("%v: mismatched error - got %v, want %v",
+				test.name, err, test.err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%v: expected %v got %v", test.name, test.want,
+				got)
+		}
+	}
+}
+
+// TestAmountFormatOptions ensures amounts are formatted according to the
+// requested decimal places, trailing zero, and grouping options.
+func TestAmountFormatOptions(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount Amount
+		unit   AmountUnit
+		opts   FormatOpts
+		want   string
+	}{
+		{
+			name:   "zero",
+			amount: 0,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: -1},
+			want:   "0.00000000 HC",
+		},
+		{
+			name:   "zero trimmed",
+			amount: 0,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: -1, TrimZeros: true},
+			want:   "0 HC",
+		},
+		{
+			name:   "dust",
+			amount: 1,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: -1},
+			want:   "0.00000001 HC",
+		},
+		{
+			name:   "dust rounded away",
+			amount: 1,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: 2},
+			want:   "0.00 HC",
+		},
+		{
+			name:   "negative",
+			amount: -123456789,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: 2},
+			want:   "-1.23 HC",
+		},
+		{
+			name:   "negative dust rounded away drops sign",
+			amount: -1,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: 2},
+			want:   "0.00 HC",
+		},
+		{
+			name:   "negative grouped",
+			amount: -123456789000000,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: 0, GroupSeparator: ","},
+			want:   "-1,234,568 HC",
+		},
+		{
+			name:   "max supply",
+			amount: MaxAmount,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: -1, GroupSeparator: ","},
+			want:   "210,000,000.00000000 HC",
+		},
+		{
+			name:   "max supply trimmed",
+			amount: MaxAmount,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: -1, TrimZeros: true, GroupSeparator: ","},
+			want:   "210,000,000 HC",
+		},
+		{
+			name:   "half to even rounds down",
+			amount: 125000,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: 4},
+			want:   "0.0012 HC",
+		},
+		{
+			name:   "half to even rounds up",
+			amount: 135000,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: 4},
+			want:   "0.0014 HC",
+		},
+		{
+			name:   "negative half to even",
+			amount: -250000000,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: 0},
+			want:   "-2 HC",
+		},
+		{
+			name:   "trim keeps significant decimals",
+			amount: 150000000,
+			unit:   AmountCoin,
+			opts:   FormatOpts{Decimals: -1, TrimZeros: true},
+			want:   "1.5 HC",
+		},
+		{
+			name:   "kilocoin",
+			amount: 123456789012345,
+			unit:   AmountKiloCoin,
+			opts:   FormatOpts{Decimals: 3, GroupSeparator: ","},
+			want:   "1,234.568 kHC",
+		},
+		{
+			name:   "atom",
+			amount: 1234567,
+			unit:   AmountAtom,
+			opts:   FormatOpts{Decimals: -1, GroupSeparator: " "},
+			want:   "1 234 567 Atom",
+		},
+		{
+			name:   "atom with decimals",
+			amount: 1234567,
+			unit:   AmountAtom,
+			opts:   FormatOpts{Decimals: 2},
+			want:   "1234567.00 Atom",
+		},
+		{
+			name:   "unit smaller than an atom",
+			amount: 5,
+			unit:   AmountUnit(-9),
+			opts:   FormatOpts{Decimals: -1},
+			want:   "50 1e-9 HC",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.amount.FormatOptions(test.unit, test.opts)
+		if got != test.want {
+			t.Errorf("%s: mismatched result - got %q, want %q",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestNewAmountFromString ensures decimal coin strings are parsed into atoms
+// exactly and malformed or out of range strings are rejected.
+func TestNewAmountFromString(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		want  Amount
+		valid bool
+	}{
+		{"whole", "12", 1200000000, true},
+		{"full precision", "12.34567890", 1234567890, true},
+		{"leading point", ".5", 50000000, true},
+		{"trailing point", "5.", 500000000, true},
+		{"zero", "0", 0, true},
+		{"one atom", "0.00000001", 1, true},
+		{"negative", "-1.5", -150000000, true},
+		{"negative leading point", "-.5", -50000000, true},
+		{"max supply", "210000000", MaxAmount, true},
+		{"negative max supply", "-210000000.00000000", -MaxAmount, true},
+		{"leading zeros", "007.00", 700000000, true},
+		{"too precise", "0.000000001", 0, false},
+		{"exceeds max supply", "210000000.00000001", 0, false},
+		{"overflow", "99999999999999999999", 0, false},
+		{"empty", "", 0, false},
+		{"only point", ".", 0, false},
+		{"only minus", "-", 0, false},
+		{"multiple points", "1.2.3", 0, false},
+		{"plus sign", "+1", 0, false},
+		{"double minus", "--1", 0, false},
+		{"comma decimal", "1,5", 0, false},
+		{"grouping separator", "1,000.5", 0, false},
+		{"space grouping", "1 000", 0, false},
+		{"apostrophe grouping", "1'000", 0, false},
+		{"whitespace", " 1", 0, false},
+		{"exponent", "1e8", 0, false},
+		{"hex", "0x10", 0, false},
+		{"unit suffix", "1 HC", 0, false},
+		{"non-ascii digit", "١", 0, false},
+	}
+
+	for _, test := range tests {
+		a, err := NewAmountFromString(test.s)
+		if (err == nil) != test.valid {
+			t.Errorf("%s: unexpected error result for %q - got %v",
+				test.name, test.s, err)
+			continue
+		}
+		if a != test.want {
+			t.Errorf("%s: mismatched amount - got %d, want %d",
+				test.name, int64(a), int64(test.want))
+		}
+	}
+}
@@ -107,3 +107,30 @@ func appDataDir(goos, appName string, roaming bool) string {
 func AppDataDir(appName string, roaming bool) string {
 	return appDataDir(runtime.GOOS, appName, roaming)
 }
+
+// appDataDirMode is the permission mode application data directories are
+// created with.  It restricts access to the owner since the directories
+// typically hold private data such as wallets and credentials.
+const appDataDirMode = 0700
+
+// ensureDir creates the passed directory along with any parents which do not
+// already exist.  Directories which are created have the permission mode
+// appDataDirMode on POSIX style operating systems, while Windows ignores the
+// mode.  A directory which already exists is left intact.
+func ensureDir(dir string) error {
+	return os.MkdirAll(dir, appDataDirMode)
+}
+
+// EnsureAppDataDir returns the same directory as AppDataDir after creating it,
+// along with any parents, when it does not already exist.  On POSIX style
+// operating systems the created directories are only accessible by the owner
+// (mode 0700), while on Windows they are created with the default permissions
+// since Windows does not use POSIX permission modes.  A directory which already
+// exists is left intact, including its permissions.
+func EnsureAppDataDir(appName string, roaming bool) (string, error) {
+	dir := AppDataDir(appName, roaming)
+	if err := ensureDir(dir); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
@@ -7,6 +7,7 @@
 package hcutil_test
 
 import (
+	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -133,3 +134,76 @@ func TestAppDataDir(t *testing.T) {
 		}
 	}
 }
+
+// TestEnsureAppDataDir ensures application data directories are created with
+// restrictive permissions and existing directories are left intact.
+func TestEnsureAppDataDir(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "appdata")
+	if err != nil {
+		t.Fatalf("TempDir: unexpected error: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Create a directory along with a missing parent.
+	dir := filepath.Join(tmpDir, "parent", ".myapp")
+	if err := hcutil.TstEnsureDir(dir); err != nil {
+		t.Fatalf("ensureDir: unexpected error: %v", err)
+	}
+	fi, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("Stat: unexpected error: %v", err)
+	}
+	if !fi.IsDir() {
+		t.Fatalf("ensureDir: %s is not a directory", dir)
+	}
+	if runtime.GOOS != "windows" && fi.Mode().Perm() != 0700 {
+		t.Errorf("ensureDir: mismatched mode - got %v, want %v",
+			fi.Mode().Perm(), os.FileMode(0700))
+	}
+
+	// An existing directory keeps its contents and permissions.
+	existing := filepath.Join(tmpDir, "existing")
+	if err := os.Mkdir(existing, 0755); err != nil {
+		t.Fatalf("Mkdir: unexpected error: %v", err)
+	}
+	if err := os.Chmod(existing, 0755); err != nil {
+		t.Fatalf("Chmod: unexpected error: %v", err)
+	}
+	file := filepath.Join(existing, "wallet.db")
+	if err := ioutil.WriteFile(file, []byte("data"), 0600); err != nil {
+		t.Fatalf("WriteFile: unexpected error: %v", err)
+	}
+	before, err := os.Stat(existing)
+	if err != nil {
+		t.Fatalf("Stat: unexpected error: %v", err)
+	}
+	if err := hcutil.TstEnsureDir(existing); err != nil {
+		t.Fatalf("ensureDir: unexpected error: %v", err)
+	}
+	after, err := os.Stat(existing)
+	if err != nil {
+		t.Fatalf("Stat: unexpected error: %v", err)
+	}
+	if after.Mode() != before.Mode() {
+		t.Errorf("ensureDir: mode of existing directory changed from "+
+			"%v to %v", before.Mode(), after.Mode())
+	}
+	if data, err := ioutil.ReadFile(file); err != nil || string(data) != "data" {
+		t.Errorf("ensureDir: contents of existing directory changed")
+	}
+
+	// A file is not able to be used as the directory.
+	if err := hcutil.TstEnsureDir(file); err == nil {
+		t.Errorf("ensureDir: expected error for existing file")
+	}
+
+	// The returned path is the same as AppDataDir.
+	got, err := hcutil.EnsureAppDataDir(".", false)
+	if err != nil {
+		t.Fatalf("EnsureAppDataDir: unexpected error: %v", err)
+	}
+	if want := hcutil.AppDataDir(".", false); got != want {
+		t.Errorf("EnsureAppDataDir: mismatched path - got %s, want %s",
+			got, want)
+	}
+}
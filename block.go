@@ -8,9 +8,15 @@ package hcutil
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 
+	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainhash"
 	"github.com/HcashOrg/hcd/wire"
 )
@@ -23,6 +29,11 @@ type OutOfRangeError string
 // mutated.
 var assertBlockImmutability = false
 
+// zeroHash is the zero value for a chainhash.Hash and is defined as a package
+// level variable to avoid the need to create a new instance every time a check
+// is needed.
+var zeroHash chainhash.Hash
+
 // BlockHeightUnknown is the value returned for a block height that is unknown.
 // This is typically because the block has not been inserted into the main chain
 // yet.
@@ -33,6 +44,19 @@ func (e OutOfRangeError) Error() string {
 	return string(e)
 }
 
+// ErrBlockTooBig describes an error where the serialized size of a block
+// exceeds the maximum allowed size.
+type ErrBlockTooBig struct {
+	Size    int // Serialized size of the block
+	MaxSize int // Maximum allowed serialized size
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e ErrBlockTooBig) Error() string {
+	return fmt.Sprintf("serialized block is too big - got %d, max %d",
+		e.Size, e.MaxSize)
+}
+
 // Block defines a cryptocurrency block that provides easier and more efficient
 // manipulation of raw blocks.  It also memoizes hashes for the block and its
 // transactions on their first access so subsequent accesses don't have to
@@ -45,6 +69,9 @@ type Block struct {
 	sTransactions   []*Tx          // Stake transactions
 	txnsGenerated   bool           // ALL wrapped transactions generated
 	sTxnsGenerated  bool           // ALL wrapped stake transactions generated
+	txLocs          []wire.TxLoc   // Cached transaction locations
+	sTxLocs         []wire.TxLoc   // Cached stake transaction locations
+	txLocsGenerated bool           // Transaction locations generated
 }
 
 // MsgBlock returns the underlying wire.MsgBlock for the Block.
@@ -55,7 +82,9 @@ func (b *Block) MsgBlock() *wire.MsgBlock {
 
 // Bytes returns the serialized bytes for the Block.  This is equivalent to
 // calling Serialize on the underlying wire.MsgBlock, however it caches the
-// result so subsequent calls are more efficient.
+// result so subsequent calls are more efficient.  Blocks created from their
+// serialized bytes, such as with NewBlockFromBytes, return those exact bytes
+// without serializing the block again.
 func (b *Block) Bytes() ([]byte, error) {
 	// Return the cached serialized bytes if it has already been generated.
 	if len(b.serializedBlock) != 0 {
@@ -76,6 +105,20 @@ func (b *Block) Bytes() ([]byte, error) {
 	return serializedBlock, nil
 }
 
+// CheckSize returns an ErrBlockTooBig error when the serialized size of the
+// Block exceeds the passed maximum size.  The block is serialized via Bytes,
+// so the serialized bytes are cached for subsequent calls.
+func (b *Block) CheckSize(maxSize int) error {
+	serializedBlock, err := b.Bytes()
+	if err != nil {
+		return err
+	}
+	if len(serializedBlock) > maxSize {
+		return ErrBlockTooBig{Size: len(serializedBlock), MaxSize: maxSize}
+	}
+	return nil
+}
+
 // BlockHeaderBytes returns the serialized bytes for the Block's header.  This is
 // equivalent to calling Serialize on the underlying wire.MsgBlock, but it
 // returns a byte slice.
@@ -114,6 +157,15 @@ func (b *Block) Hash() *chainhash.Hash {
 	return &b.hash
 }
 
+// HeaderHash returns the BLAKE256 hash of the serialized 180 byte header of the
+// Block, as used when mining.  The block identifier hash is defined as the hash
+// of the header, so this is always the same as the hash returned by Hash and
+// is likewise cached.  It is returned by value, so modifying it does not
+// affect the cached hash.
+func (b *Block) HeaderHash() chainhash.Hash {
+	return *b.Hash()
+}
+
 // Tx returns a wrapped transaction (hcutil.Tx) for the transaction at the
 // specified index in the Block.  The supplied index is 0 based.  That is to
 // say, the first transaction in the block is txNum 0.  This is nearly
@@ -278,10 +330,73 @@ func (b *Block) STxHash(txNum int) (*chainhash.Hash, error) {
 	return tx.Hash(), nil
 }
 
-// TxLoc returns the offsets and lengths of each transaction in a raw block.
-// It is used to allow fast indexing into transactions within the raw byte
-// stream.
+// shortIDMask is the mask applied to SipHash results to form the 6 byte short
+// transaction IDs returned by ShortIDs.
+const shortIDMask = 1<<48 - 1
+
+// shortIDKeys returns the SipHash key used to calculate the short transaction
+// IDs of the block for the passed nonce.  The key is the first 16 bytes of the
+// hash of the block hash followed by the little-endian nonce.
+func (b *Block) shortIDKeys(nonce uint64) (uint64, uint64) {
+	var buf [chainhash.HashSize + 8]byte
+	copy(buf[:], b.Hash()[:])
+	binary.LittleEndian.PutUint64(buf[chainhash.HashSize:], nonce)
+	key := chainhash.HashH(buf[:])
+	return binary.LittleEndian.Uint64(key[0:8]),
+		binary.LittleEndian.Uint64(key[8:16])
+}
+
+// shortTxID returns the short ID of the passed transaction for the SipHash key
+// formed by k0 and k1.
+func shortTxID(k0, k1 uint64, tx *Tx) uint64 {
+	return sipHash24(k0, k1, tx.Hash()[:]) & shortIDMask
+}
+
+// ShortIDs returns the 6 byte short IDs of the transactions in the block, in
+// the style of compact block announcements, for the passed nonce.  The IDs of
+// the regular transactions are followed by those of the stake transactions.
+//
+// Each ID is the lower 48 bits of the SipHash-2-4 of the transaction hash, as
+// returned by Tx.Hash, keyed by the first 16 bytes of the hash of the block
+// hash followed by the little-endian nonce.  Salting the IDs with the block
+// hash and a nonce chosen by the announcer prevents precomputing colliding
+// transactions, however the IDs are short enough that unintended collisions
+// are possible, so a receiver must be prepared to request the full
+// transactions when they can not be matched unambiguously.
+func (b *Block) ShortIDs(nonce uint64) []uint64 {
+	k0, k1 := b.shortIDKeys(nonce)
+	txns := b.Transactions()
+	stxns := b.STransactions()
+	shortIDs := make([]uint64, 0, len(txns)+len(stxns))
+	for _, tx := range txns {
+		shortIDs = append(shortIDs, shortTxID(k0, k1, tx))
+	}
+	for _, tx := range stxns {
+		shortIDs = append(shortIDs, shortTxID(k0, k1, tx))
+	}
+	return shortIDs
+}
+
+// MatchShortID returns whether or not the passed short ID is the short ID of
+// the passed transaction for the block and nonce as calculated by ShortIDs.
+// The transaction does not need to be part of the block, which allows a
+// receiver to match announced short IDs against the transactions it already
+// knows about.
+func (b *Block) MatchShortID(shortID uint64, nonce uint64, tx *Tx) bool {
+	k0, k1 := b.shortIDKeys(nonce)
+	return shortTxID(k0, k1, tx) == shortID
+}
+
+// TxLoc returns the offsets and lengths of each transaction in the regular and
+// stake transaction trees of a raw block.  It is used to allow fast indexing
+// into transactions within the raw byte stream.  The locations are cached on
+// the first successful call so subsequent calls are more efficient.
 func (b *Block) TxLoc() ([]wire.TxLoc, []wire.TxLoc, error) {
+	// Return the cached locations if they have already been generated.
+	if b.txLocsGenerated {
+		return b.txLocs, b.sTxLocs, nil
+	}
+
 	rawMsg, err := b.Bytes()
 	if err != nil {
 		return nil, nil, err
@@ -293,7 +408,292 @@ func (b *Block) TxLoc() ([]wire.TxLoc, []wire.TxLoc, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	return txLocs, sTxLocs, err
+
+	// Cache the locations and return them.
+	b.txLocs = txLocs
+	b.sTxLocs = sTxLocs
+	b.txLocsGenerated = true
+	return txLocs, sTxLocs, nil
+}
+
+// merkleLeaves returns the leaves of the merkle tree of the regular
+// transaction tree of the Block.  As required by consensus, they are the full
+// hashes of the transactions, which commit to both the prefix and the witness,
+// rather than the transaction hashes returned by Hash.
+func (b *Block) merkleLeaves() []chainhash.Hash {
+	leaves := make([]chainhash.Hash, 0, len(b.msgBlock.Transactions))
+	for _, tx := range b.Transactions() {
+		leaves = append(leaves, *tx.WitnessHash())
+	}
+	return leaves
+}
+
+// MerkleProof returns the sibling hashes along the path from the regular
+// transaction at the passed index to the merkle root of the regular transaction
+// tree of the Block.  The proof may be checked with VerifyMerkleProof, which
+// expects the full hash of the transaction as returned by the WitnessHash
+// method of Tx rather than the hash returned by Hash.  The supplied index is 0
+// based.
+func (b *Block) MerkleProof(txIndex int) ([]chainhash.Hash, error) {
+	numTx := len(b.msgBlock.Transactions)
+	if txIndex < 0 || txIndex >= numTx {
+		str := fmt.Sprintf("transaction index %d is out of range - max %d",
+			txIndex, numTx-1)
+		return nil, OutOfRangeError(str)
+	}
+
+	return merkleProof(b.merkleLeaves(), txIndex), nil
+}
+
+// BuildMerkleTree returns the merkle tree of the regular transaction tree of the
+// Block as a linear array, as used by SPV proofs.  The transaction hashes come
+// first, padded with nil entries up to the next power of two, followed by each
+// level of internal nodes in turn, so the final element is the merkle root.
+// Nodes with no children are nil.
+//
+// The leaves are the full transaction hashes committed to by consensus, which
+// are the same as those used by MerkleProof, so the root is the merkle root of
+// the block header and is consistent with the proofs MerkleProof returns.
+func (b *Block) BuildMerkleTree() []*chainhash.Hash {
+	return buildMerkleTreeStore(b.merkleLeaves())
+}
+
+// isNullOutPoint determines whether or not a previous transaction output point
+// is set to the null value used by coinbase and stakebase inputs.
+func isNullOutPoint(outPoint *wire.OutPoint) bool {
+	return outPoint.Index == wire.MaxPrevOutIndex &&
+		outPoint.Hash == zeroHash
+}
+
+// SpentOutpoints returns the previous outpoints referenced by every input in
+// both the regular and stake transaction trees of the Block.  Inputs which do
+// not spend a previous output, such as the coinbase and stakebase inputs, are
+// skipped.  The outpoints are returned in block order with the regular tree
+// first.
+func (b *Block) SpentOutpoints() []wire.OutPoint {
+	var spent []wire.OutPoint
+	for _, txns := range [][]*wire.MsgTx{b.msgBlock.Transactions,
+		b.msgBlock.STransactions} {
+
+		for _, mtx := range txns {
+			for _, txIn := range mtx.TxIn {
+				if isNullOutPoint(&txIn.PreviousOutPoint) {
+					continue
+				}
+				spent = append(spent, txIn.PreviousOutPoint)
+			}
+		}
+	}
+	return spent
+}
+
+// CreatedOutpoints returns the outpoints of every output created by the
+// transactions in both the regular and stake transaction trees of the Block.
+// The outpoints are returned in block order with the regular tree first.
+func (b *Block) CreatedOutpoints() []wire.OutPoint {
+	var created []wire.OutPoint
+	for _, txns := range [][]*Tx{b.Transactions(), b.STransactions()} {
+		for _, tx := range txns {
+			hash := tx.Hash()
+			for i := range tx.msgTx.TxOut {
+				created = append(created, wire.OutPoint{
+					Hash:  *hash,
+					Index: uint32(i),
+					Tree:  tx.Tree(),
+				})
+			}
+		}
+	}
+	return created
+}
+
+// IndexedOutput describes a transaction output created by a block along with
+// its coordinates within the block.
+type IndexedOutput struct {
+	TxIndex     int         // Index of the transaction within its tree
+	OutputIndex uint32      // Index of the output within its transaction
+	Tree        int8        // Transaction tree of the transaction
+	TxOut       *wire.TxOut // The output itself
+}
+
+// OrderedOutputs returns every output created by the transactions in both the
+// regular and stake transaction trees of the Block along with their
+// coordinates.  The outputs are in canonical order, which is the regular tree
+// followed by the stake tree, then by transaction index within each tree, and
+// then by output index within each transaction.  This makes the order suitable
+// for deterministic insertion into an index.
+func (b *Block) OrderedOutputs() []IndexedOutput {
+	var outputs []IndexedOutput
+	trees := []struct {
+		tree int8
+		txns []*wire.MsgTx
+	}{
+		{wire.TxTreeRegular, b.msgBlock.Transactions},
+		{wire.TxTreeStake, b.msgBlock.STransactions},
+	}
+	for _, t := range trees {
+		for txIndex, mtx := range t.txns {
+			for i, txOut := range mtx.TxOut {
+				outputs = append(outputs, IndexedOutput{
+					TxIndex:     txIndex,
+					OutputIndex: uint32(i),
+					Tree:        t.tree,
+					TxOut:       txOut,
+				})
+			}
+		}
+	}
+	return outputs
+}
+
+// TotalFees returns the sum of the fees paid by the regular transactions in the
+// Block, excluding the coinbase.  The fee of each transaction is the value of
+// its inputs, as provided by fetchInput, less the value of its outputs.  Any
+// error returned by fetchInput is returned unmodified, and an error is returned
+// when a transaction spends more than the value of its inputs.
+func (b *Block) TotalFees(fetchInput func(wire.OutPoint) (Amount, error)) (Amount, error) {
+	var totalFees Amount
+	for _, tx := range b.Transactions() {
+		msgTx := tx.MsgTx()
+		if isCoinBase(msgTx) {
+			continue
+		}
+
+		var totalIn, totalOut Amount
+		for _, txIn := range msgTx.TxIn {
+			amt, err := fetchInput(txIn.PreviousOutPoint)
+			if err != nil {
+				return 0, err
+			}
+			totalIn += amt
+		}
+		for _, txOut := range msgTx.TxOut {
+			totalOut += Amount(txOut.Value)
+		}
+		if totalIn < totalOut {
+			return 0, fmt.Errorf("transaction %v spends %v which is "+
+				"more than its input value of %v", tx.Hash(),
+				totalOut, totalIn)
+		}
+		totalFees += totalIn - totalOut
+	}
+	return totalFees, nil
+}
+
+// WriteTxCSV writes a CSV export of the regular transactions in the Block to
+// w.  A header row is written first, followed by one row per transaction with
+// its hash, number of inputs, number of outputs, and total output value in
+// coins.  The network parameters are not needed by the current columns and are
+// accepted so columns which depend on them, such as addresses, can be added
+// without changing the signature.
+func (b *Block) WriteTxCSV(w io.Writer, net *chaincfg.Params) error {
+	csvWriter := csv.NewWriter(w)
+	err := csvWriter.Write([]string{"txid", "inputs", "outputs", "value"})
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range b.Transactions() {
+		msgTx := tx.MsgTx()
+		var value Amount
+		for _, txOut := range msgTx.TxOut {
+			value += Amount(txOut.Value)
+		}
+		err := csvWriter.Write([]string{
+			tx.Hash().String(),
+			strconv.Itoa(len(msgTx.TxIn)),
+			strconv.Itoa(len(msgTx.TxOut)),
+			formatCoinAtoms(value),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// formatCoinAtoms formats the passed amount in coins with eight decimal
+// places.  The string is built from the integer atoms rather than the
+// floating point value returned by ToCoin so large amounts do not lose
+// precision.
+func formatCoinAtoms(a Amount) string {
+	var sign string
+	atoms := uint64(a)
+	if a < 0 {
+		sign = "-"
+		atoms = -atoms
+	}
+	return fmt.Sprintf("%s%d.%08d", sign, atoms/AtomsPerCoin,
+		atoms%AtomsPerCoin)
+}
+
+// blockJSON describes the JSON encoding of a Block.  The field names match
+// those used by the verbose block results of the RPC server.
+type blockJSON struct {
+	Hash         string   `json:"hash"`
+	Version      int32    `json:"version"`
+	PreviousHash string   `json:"previousblockhash"`
+	MerkleRoot   string   `json:"merkleroot"`
+	StakeRoot    string   `json:"stakeroot"`
+	VoteBits     uint16   `json:"votebits"`
+	FinalState   string   `json:"finalstate"`
+	Voters       uint16   `json:"voters"`
+	FreshStake   uint8    `json:"freshstake"`
+	Revocations  uint8    `json:"revocations"`
+	PoolSize     uint32   `json:"poolsize"`
+	Bits         string   `json:"bits"`
+	SBits        float64  `json:"sbits"`
+	Height       uint32   `json:"height"`
+	Size         uint32   `json:"size"`
+	Time         int64    `json:"time"`
+	Nonce        uint32   `json:"nonce"`
+	ExtraData    string   `json:"extradata"`
+	Tx           []string `json:"tx"`
+	STx          []string `json:"stx"`
+}
+
+// MarshalJSON returns the JSON encoding of the Block.  It consists of the
+// block hash, the fields of the block header, and the hashes of the regular
+// and stake transactions in block order.  Hashes and byte arrays are encoded
+// as hex strings, the difficulty bits as a hex string, the stake difficulty in
+// coins, and the timestamp in seconds since the Unix epoch.  The transactions
+// themselves are not included.
+//
+// This is part of the json.Marshaler interface implementation.
+func (b *Block) MarshalJSON() ([]byte, error) {
+	header := &b.msgBlock.Header
+	txHashes := func(txns []*Tx) []string {
+		hashes := make([]string, 0, len(txns))
+		for _, tx := range txns {
+			hashes = append(hashes, tx.Hash().String())
+		}
+		return hashes
+	}
+
+	return json.Marshal(&blockJSON{
+		Hash:         b.Hash().String(),
+		Version:      header.Version,
+		PreviousHash: header.PrevBlock.String(),
+		MerkleRoot:   header.MerkleRoot.String(),
+		StakeRoot:    header.StakeRoot.String(),
+		VoteBits:     header.VoteBits,
+		FinalState:   hex.EncodeToString(header.FinalState[:]),
+		Voters:       header.Voters,
+		FreshStake:   header.FreshStake,
+		Revocations:  header.Revocations,
+		PoolSize:     header.PoolSize,
+		Bits:         strconv.FormatInt(int64(header.Bits), 16),
+		SBits:        Amount(header.SBits).ToCoin(),
+		Height:       header.Height,
+		Size:         header.Size,
+		Time:         header.Timestamp.Unix(),
+		Nonce:        header.Nonce,
+		ExtraData:    hex.EncodeToString(header.ExtraData[:]),
+		Tx:           txHashes(b.Transactions()),
+		STx:          txHashes(b.STransactions()),
+	})
 }
 
 // Height returns a casted int64 height from the block header.
@@ -304,6 +704,28 @@ func (b *Block) Height() int64 {
 	return int64(b.msgBlock.Header.Height)
 }
 
+// ValidateBlockChain returns an error when the passed blocks, in order, do not
+// form a chain.  Each block after the first must reference the hash of the
+// block before it as its previous block and have a height one greater than it.
+// Only the linkage of the blocks is checked, not their validity.
+func ValidateBlockChain(blocks []*Block) error {
+	for i := 1; i < len(blocks); i++ {
+		prev, block := blocks[i-1], blocks[i]
+		prevHash := &block.msgBlock.Header.PrevBlock
+		if !prevHash.IsEqual(prev.Hash()) {
+			return fmt.Errorf("block %d (%v) references previous block "+
+				"%v instead of %v", i, block.Hash(), prevHash,
+				prev.Hash())
+		}
+		if block.Height() != prev.Height()+1 {
+			return fmt.Errorf("block %d (%v) has height %d instead "+
+				"of %d", i, block.Hash(), block.Height(),
+				prev.Height()+1)
+		}
+	}
+	return nil
+}
+
 // NewBlock returns a new instance of a block given an underlying
 // wire.MsgBlock.  See Block.
 func NewBlock(msgBlock *wire.MsgBlock) *Block {
@@ -376,7 +798,9 @@ func NewBlockDeepCopy(msgBlock *wire.MsgBlock) *Block {
 }
 
 // NewBlockFromBytes returns a new instance of a block given the
-// serialized bytes.  See Block.
+// serialized bytes.  The bytes are only deserialized once and the passed slice
+// is retained and returned unmodified by Bytes, so the caller must not modify
+// it afterwards.  See Block.
 func NewBlockFromBytes(serializedBlock []byte) (*Block, error) {
 	br := bytes.NewReader(serializedBlock)
 	b, err := NewBlockFromReader(br)
@@ -8,11 +8,15 @@ package hcutil_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"testing"
 	"time"
 
+	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainhash"
 	"github.com/HcashOrg/hcd/wire"
 	"github.com/HcashOrg/hcutil"
@@ -166,6 +170,128 @@ func TestBlock(t *testing.T) {
 	}
 }
 
+// TestBlockTxLocExtract ensures the transactions of both trees are able to be
+// extracted from the serialized block with the locations returned by TxLoc and
+// that the locations are cached.
+func TestBlockTxLocExtract(t *testing.T) {
+	msgBlock := Block100000
+	msgBlock.STransactions = []*wire.MsgTx{voteTx(), p2pkhTx()}
+	b := hcutil.NewBlock(&msgBlock)
+
+	txLocs, sTxLocs, err := b.TxLoc()
+	if err != nil {
+		t.Fatalf("TxLoc: unexpected error: %v", err)
+	}
+	rawBlock, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: unexpected error: %v", err)
+	}
+
+	trees := []struct {
+		name   string
+		locs   []wire.TxLoc
+		numTxs int
+		tx     func(int) (*hcutil.Tx, error)
+	}{
+		{"regular", txLocs, len(msgBlock.Transactions), b.Tx},
+		{"stake", sTxLocs, len(msgBlock.STransactions), b.STx},
+	}
+	for _, tree := range trees {
+		if len(tree.locs) != tree.numTxs {
+			t.Errorf("TxLoc: mismatched number of %s locations - "+
+				"got %d, want %d", tree.name, len(tree.locs),
+				tree.numTxs)
+			continue
+		}
+		for i, loc := range tree.locs {
+			tx, err := tree.tx(i)
+			if err != nil {
+				t.Fatalf("%s tx %d: unexpected error: %v",
+					tree.name, i, err)
+			}
+			want, err := tx.MsgTx().Bytes()
+			if err != nil {
+				t.Fatalf("%s tx %d: Bytes: unexpected error: %v",
+					tree.name, i, err)
+			}
+			got := rawBlock[loc.TxStart : loc.TxStart+loc.TxLen]
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s tx %d: mismatched bytes - got %x, "+
+					"want %x", tree.name, i, got, want)
+			}
+		}
+	}
+
+	// The locations are only generated once.
+	txLocs2, sTxLocs2, err := b.TxLoc()
+	if err != nil {
+		t.Fatalf("TxLoc: unexpected error: %v", err)
+	}
+	if &txLocs2[0] != &txLocs[0] || &sTxLocs2[0] != &sTxLocs[0] {
+		t.Errorf("TxLoc: locations were not cached")
+	}
+}
+
+// TestValidateBlockChain ensures chains of blocks are validated by their
+// previous block hashes and heights.
+func TestValidateBlockChain(t *testing.T) {
+	// makeChain returns a chain of three blocks built on Block100000 where
+	// each block references the hash of the one before it.
+	makeChain := func() []*wire.MsgBlock {
+		chain := make([]*wire.MsgBlock, 3)
+		for i := range chain {
+			msgBlock := Block100000
+			if i > 0 {
+				msgBlock.Header.PrevBlock = chain[i-1].BlockHash()
+				msgBlock.Header.Height = chain[i-1].Header.Height + 1
+			}
+			chain[i] = &msgBlock
+		}
+		return chain
+	}
+	toBlocks := func(chain []*wire.MsgBlock) []*hcutil.Block {
+		blocks := make([]*hcutil.Block, 0, len(chain))
+		for _, msgBlock := range chain {
+			blocks = append(blocks, hcutil.NewBlock(msgBlock))
+		}
+		return blocks
+	}
+
+	chain := makeChain()
+	if err := hcutil.ValidateBlockChain(toBlocks(chain)); err != nil {
+		t.Errorf("ValidateBlockChain: unexpected error: %v", err)
+	}
+	for _, blocks := range [][]*hcutil.Block{nil, toBlocks(chain[:1])} {
+		if err := hcutil.ValidateBlockChain(blocks); err != nil {
+			t.Errorf("ValidateBlockChain: unexpected error for %d "+
+				"blocks: %v", len(blocks), err)
+		}
+	}
+
+	// Break the link between the second and third blocks.
+	chain = makeChain()
+	chain[2].Header.PrevBlock = chain[0].BlockHash()
+	if err := hcutil.ValidateBlockChain(toBlocks(chain)); err == nil {
+		t.Errorf("ValidateBlockChain: expected error for broken link")
+	}
+
+	// Skip a height while keeping the hashes linked.
+	chain = makeChain()
+	chain[1].Header.Height++
+	chain[2].Header.PrevBlock = chain[1].BlockHash()
+	if err := hcutil.ValidateBlockChain(toBlocks(chain)); err == nil {
+		t.Errorf("ValidateBlockChain: expected error for skipped height")
+	}
+
+	// Blocks out of order do not form a chain.
+	chain = makeChain()
+	chain[1], chain[2] = chain[2], chain[1]
+	if err := hcutil.ValidateBlockChain(toBlocks(chain)); err == nil {
+		t.Errorf("ValidateBlockChain: expected error for blocks out " +
+			"of order")
+	}
+}
+
 // TestNewBlockFromBytes tests creation of a Block from serialized bytes.
 func TestNewBlockFromBytes(t *testing.T) {
 	// Serialize the test block.
@@ -196,6 +322,14 @@ func TestNewBlockFromBytes(t *testing.T) {
 			spew.Sdump(block100000Bytes))
 	}
 
+	// Ensure the exact slice the block was created from is returned rather
+	// than a new serialization.
+	if len(serializedBytes) != len(block100000Bytes) ||
+		&serializedBytes[0] != &block100000Bytes[0] {
+
+		t.Errorf("Bytes: did not return the original slice")
+	}
+
 	// Ensure the generated MsgBlock is correct.
 	if msgBlock := b.MsgBlock(); !reflect.DeepEqual(msgBlock, &Block100000) {
 		t.Errorf("MsgBlock: mismatched MsgBlock - got %v, want %v",
@@ -203,6 +337,37 @@ func TestNewBlockFromBytes(t *testing.T) {
 	}
 }
 
+// BenchmarkNewBlockFromBytes benchmarks creating a block from its serialized
+// bytes and retrieving them.
+func BenchmarkNewBlockFromBytes(b *testing.B) {
+	serialized, err := Block100000.Bytes()
+	if err != nil {
+		b.Fatalf("Bytes: unexpected error: %v", err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		block, err := hcutil.NewBlockFromBytes(serialized)
+		if err != nil {
+			b.Fatalf("NewBlockFromBytes: unexpected error: %v", err)
+		}
+		block.Bytes()
+	}
+}
+
+// BenchmarkNewBlockSerialize benchmarks creating a block from a MsgBlock and
+// serializing it.
+func BenchmarkNewBlockSerialize(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		block := hcutil.NewBlock(&Block100000)
+		if _, err := block.Bytes(); err != nil {
+			b.Fatalf("Bytes: unexpected error: %v", err)
+		}
+	}
+}
+
 // TestNewBlockFromBlockAndBytes tests creation of a Block from a MsgBlock and
 // raw bytes.
 func TestNewBlockFromBlockAndBytes(t *testing.T) {
@@ -304,6 +469,484 @@ func TestBlockErrors(t *testing.T) {
 	}
 }
 
+// TestBlockOutpoints ensures the outpoints spent and created by a block are
+// reported as expected.
+func TestBlockOutpoints(t *testing.T) {
+	b := hcutil.NewBlock(&Block100000)
+
+	// Every transaction other than the coinbase spends a single previous
+	// output.
+	wantSpent := []wire.OutPoint{
+		Block100000.Transactions[1].TxIn[0].PreviousOutPoint,
+		Block100000.Transactions[2].TxIn[0].PreviousOutPoint,
+		Block100000.Transactions[3].TxIn[0].PreviousOutPoint,
+	}
+	spent := b.SpentOutpoints()
+	if !reflect.DeepEqual(spent, wantSpent) {
+		t.Errorf("SpentOutpoints: mismatched outpoints - got %v, want %v",
+			spew.Sdump(spent), spew.Sdump(wantSpent))
+	}
+
+	// Every output of every transaction is created by the block.
+	var wantCreated []wire.OutPoint
+	for _, mtx := range Block100000.Transactions {
+		hash := mtx.TxHash()
+		for i := range mtx.TxOut {
+			wantCreated = append(wantCreated, wire.OutPoint{
+				Hash:  hash,
+				Index: uint32(i),
+				Tree:  wire.TxTreeRegular,
+			})
+		}
+	}
+	if len(wantCreated) != 6 {
+		t.Fatalf("unexpected number of outputs in test block - got %d, "+
+			"want 6", len(wantCreated))
+	}
+	created := b.CreatedOutpoints()
+	if !reflect.DeepEqual(created, wantCreated) {
+		t.Errorf("CreatedOutpoints: mismatched outpoints - got %v, "+
+			"want %v", spew.Sdump(created), spew.Sdump(wantCreated))
+	}
+}
+
+// TestBlockOrderedOutputs ensures the outputs of a block are returned with their
+// coordinates in canonical order.
+func TestBlockOrderedOutputs(t *testing.T) {
+	regularTx := wire.NewMsgTx()
+	regularTx.AddTxOut(wire.NewTxOut(100, []byte{0x51}))
+	regularTx.AddTxOut(wire.NewTxOut(200, []byte{0x52}))
+	stakeTx := wire.NewMsgTx()
+	stakeTx.AddTxOut(wire.NewTxOut(300, []byte{0x53}))
+
+	msgBlock := &wire.MsgBlock{
+		Transactions:  []*wire.MsgTx{regularTx},
+		STransactions: []*wire.MsgTx{stakeTx},
+	}
+	outputs := hcutil.NewBlock(msgBlock).OrderedOutputs()
+
+	want := []hcutil.IndexedOutput{
+		{TxIndex: 0, OutputIndex: 0, Tree: wire.TxTreeRegular,
+			TxOut: regularTx.TxOut[0]},
+		{TxIndex: 0, OutputIndex: 1, Tree: wire.TxTreeRegular,
+			TxOut: regularTx.TxOut[1]},
+		{TxIndex: 0, OutputIndex: 0, Tree: wire.TxTreeStake,
+			TxOut: stakeTx.TxOut[0]},
+	}
+	if !reflect.DeepEqual(outputs, want) {
+		t.Errorf("OrderedOutputs: mismatched outputs - got %v, want %v",
+			spew.Sdump(outputs), spew.Sdump(want))
+	}
+}
+
+// TestBlockShortIDs ensures the short transaction IDs of a block identify its
+// transactions and depend on the nonce.
+func TestBlockShortIDs(t *testing.T) {
+	stakeTx := wire.NewMsgTx()
+	stakeTx.AddTxOut(wire.NewTxOut(300, []byte{0x53}))
+	msgBlock := Block100000
+	msgBlock.STransactions = []*wire.MsgTx{stakeTx}
+	b := hcutil.NewBlock(&msgBlock)
+	txns := append(b.Transactions(), b.STransactions()...)
+
+	const nonce = 0x0123456789abcdef
+	shortIDs := b.ShortIDs(nonce)
+	if len(shortIDs) != len(txns) {
+		t.Fatalf("ShortIDs: mismatched number of IDs - got %d, want %d",
+			len(shortIDs), len(txns))
+	}
+	for i, shortID := range shortIDs {
+		if shortID>>48 != 0 {
+			t.Errorf("ShortIDs #%d: ID %x is longer than 6 bytes", i,
+				shortID)
+		}
+		for j, tx := range txns {
+			match := b.MatchShortID(shortID, nonce, tx)
+			if match != (i == j) {
+				t.Errorf("MatchShortID(#%d, tx #%d): mismatched "+
+					"result - got %v, want %v", i, j, match,
+					i == j)
+			}
+		}
+		if b.MatchShortID(shortID, nonce+1, txns[i]) {
+			t.Errorf("MatchShortID #%d: unexpected match with "+
+				"another nonce", i)
+		}
+	}
+
+	// The IDs are salted by the block hash.
+	otherBlock := msgBlock
+	otherBlock.Header.Nonce++
+	otherIDs := hcutil.NewBlock(&otherBlock).ShortIDs(nonce)
+	if reflect.DeepEqual(otherIDs, shortIDs) {
+		t.Errorf("ShortIDs: IDs do not depend on the block hash")
+	}
+}
+
+// TestBlockWriteTxCSV ensures the CSV export of the regular transactions in a
+// block is as expected.
+func TestBlockWriteTxCSV(t *testing.T) {
+	b := hcutil.NewBlock(&Block100000)
+
+	wantCSV := "txid,inputs,outputs,value\n" +
+		"1cbd9fe1a143a265cc819ff9d8132a7cbc4ca48eb68c0de39cfdf7ecf42cbbd1," +
+		"1,1,50.00000000\n" +
+		"f3f9bc9473b6fe18d66e3ac2a1a95b6317b280f4e6687a074075b56aebf1eb53," +
+		"1,2,50.00000000\n" +
+		"ba2ed6210a561a4dab34ec8668ad61ec97f126826dae893719dff7383b9d6928," +
+		"1,2,3.00000000\n" +
+		"c5dde35b55b856cf73b2d85737c68b0dcfdaad01d0271ee509f3a7efacc025b3," +
+		"1,1,0.01000000\n"
+
+	var buf bytes.Buffer
+	if err := b.WriteTxCSV(&buf, &chaincfg.MainNetParams); err != nil {
+		t.Fatalf("WriteTxCSV: unexpected error: %v", err)
+	}
+	if got := buf.String(); got != wantCSV {
+		t.Errorf("WriteTxCSV: mismatched CSV - got:\n%s\nwant:\n%s", got,
+			wantCSV)
+	}
+
+	// Ensure large values are exported without losing precision.
+	largeTx := wire.NewMsgTx()
+	largeTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, 0, nil))
+	largeTx.AddTxOut(wire.NewTxOut(9223372036854775807, nil))
+	largeBlock := hcutil.NewBlock(&wire.MsgBlock{
+		Transactions: []*wire.MsgTx{largeTx},
+	})
+	wantCSV = "txid,inputs,outputs,value\n" + largeTx.TxHash().String() +
+		",1,1,92233720368.54775807\n"
+	buf.Reset()
+	err := largeBlock.WriteTxCSV(&buf, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("WriteTxCSV: unexpected error: %v", err)
+	}
+	if got := buf.String(); got != wantCSV {
+		t.Errorf("WriteTxCSV: mismatched CSV - got:\n%s\nwant:\n%s", got,
+			wantCSV)
+	}
+}
+
+// TestBlockTotalFees ensures the fees of the regular transactions of a block are
+// summed using the provided input lookup and lookup errors are propagated.
+func TestBlockTotalFees(t *testing.T) {
+	b := hcutil.NewBlock(&Block100000)
+
+	// The non-coinbase transactions pay 50, 3, and 0.01 coins, so the
+	// inputs below pay fees of 0.01, 0.02, and 0.005 coins, respectively.
+	spent := b.SpentOutpoints()
+	if len(spent) != 3 {
+		t.Fatalf("SpentOutpoints: unexpected number of outpoints - got "+
+			"%d, want %d", len(spent), 3)
+	}
+	inputs := map[wire.OutPoint]hcutil.Amount{
+		spent[0]: 5001000000,
+		spent[1]: 302000000,
+		spent[2]: 1500000,
+	}
+	fetchInput := func(op wire.OutPoint) (hcutil.Amount, error) {
+		amt, ok := inputs[op]
+		if !ok {
+			return 0, fmt.Errorf("unknown outpoint %v", op)
+		}
+		return amt, nil
+	}
+
+	fees, err := b.TotalFees(fetchInput)
+	if err != nil {
+		t.Fatalf("TotalFees: unexpected error: %v", err)
+	}
+	if want := hcutil.Amount(3500000); fees != want {
+		t.Errorf("TotalFees: mismatched fees - got %v, want %v", fees,
+			want)
+	}
+
+	// Ensure lookup errors are returned unmodified.
+	errLookup := errors.New("lookup failed")
+	_, err = b.TotalFees(func(wire.OutPoint) (hcutil.Amount, error) {
+		return 0, errLookup
+	})
+	if err != errLookup {
+		t.Errorf("TotalFees: mismatched error - got %v, want %v", err,
+			errLookup)
+	}
+
+	// Ensure a transaction spending more than its inputs is rejected.
+	inputs[spent[1]] = 200000000
+	if _, err := b.TotalFees(fetchInput); err == nil {
+		t.Errorf("TotalFees: expected error for transaction spending " +
+			"more than its inputs")
+	}
+}
+
+// TestBlockMerkleProof ensures merkle proofs for the regular transactions of a
+// block verify against the merkle root of the regular transaction tree.
+func TestBlockMerkleProof(t *testing.T) {
+	// hashPair returns the merkle tree node for the passed children.
+	hashPair := func(left, right chainhash.Hash) chainhash.Hash {
+		return chainhash.HashH(append(left[:], right[:]...))
+	}
+
+	// Ensure the proof for the coinbase of the mainnet genesis block
+	// verifies against the merkle root committed to by its header.
+	genesis := hcutil.NewBlock(chaincfg.MainNetParams.GenesisBlock)
+	genesisProof, err := genesis.MerkleProof(0)
+	if err != nil {
+		t.Fatalf("MerkleProof (genesis): unexpected error: %v", err)
+	}
+	genesisRoot := genesis.MsgBlock().Header.MerkleRoot
+	coinbaseHash := *genesis.Transactions()[0].WitnessHash()
+	if !hcutil.VerifyMerkleProof(coinbaseHash, genesisProof, 0,
+		genesisRoot) {
+
+		t.Errorf("VerifyMerkleProof (genesis): proof rejected for "+
+			"header merkle root %v", genesisRoot)
+	}
+	prefixHash := *genesis.Transactions()[0].Hash()
+	if hcutil.VerifyMerkleProof(prefixHash, genesisProof, 0, genesisRoot) {
+		t.Errorf("VerifyMerkleProof (genesis): proof accepted for " +
+			"transaction prefix hash")
+	}
+
+	b := hcutil.NewBlock(&Block100000)
+	var hashes []chainhash.Hash
+	for _, tx := range b.Transactions() {
+		hashes = append(hashes, *tx.WitnessHash())
+	}
+	root := hashPair(hashPair(hashes[0], hashes[1]),
+		hashPair(hashes[2], hashes[3]))
+
+	for i, txHash := range hashes {
+		proof, err := b.MerkleProof(i)
+		if err != nil {
+			t.Errorf("MerkleProof #%d: unexpected error: %v", i, err)
+			continue
+		}
+		if len(proof) != 2 {
+			t.Errorf("MerkleProof #%d: mismatched proof length - got "+
+				"%d, want 2", i, len(proof))
+			continue
+		}
+		if !hcutil.VerifyMerkleProof(txHash, proof, i, root) {
+			t.Errorf("VerifyMerkleProof #%d: valid proof rejected", i)
+		}
+
+		// The proof must not verify for any other index or hash.
+		if hcutil.VerifyMerkleProof(txHash, proof, i^1, root) {
+			t.Errorf("VerifyMerkleProof #%d: proof accepted for "+
+				"wrong index", i)
+		}
+		if hcutil.VerifyMerkleProof(hashes[(i+1)%len(hashes)], proof,
+			i, root) {
+			t.Errorf("VerifyMerkleProof #%d: proof accepted for "+
+				"wrong hash", i)
+		}
+	}
+
+	// Ensure the last transaction of an odd-sized tree is paired with
+	// itself.
+	oddMsgBlock := Block100000
+	oddMsgBlock.Transactions = Block100000.Transactions[:3]
+	oddBlock := hcutil.NewBlock(&oddMsgBlock)
+	oddRoot := hashPair(hashPair(hashes[0], hashes[1]),
+		hashPair(hashes[2], hashes[2]))
+	proof, err := oddBlock.MerkleProof(2)
+	if err != nil {
+		t.Fatalf("MerkleProof (odd): unexpected error: %v", err)
+	}
+	if !hcutil.VerifyMerkleProof(hashes[2], proof, 2, oddRoot) {
+		t.Errorf("VerifyMerkleProof (odd): valid proof rejected")
+	}
+
+	// Ensure out of range indices are rejected.
+	for _, index := range []int{-1, len(hashes)} {
+		_, err := b.MerkleProof(index)
+		if _, ok := err.(hcutil.OutOfRangeError); !ok {
+			t.Errorf("MerkleProof (%d): mismatched error - got %T, "+
+				"want OutOfRangeError", index, err)
+		}
+	}
+}
+
+// TestBlockBuildMerkleTree ensures the full merkle tree of the regular
+// transaction tree is built with the root as the final element.
+func TestBlockBuildMerkleTree(t *testing.T) {
+	// hashPair returns the merkle tree node for the passed children.
+	hashPair := func(left, right chainhash.Hash) chainhash.Hash {
+		return chainhash.HashH(append(left[:], right[:]...))
+	}
+
+	// Ensure the root of the tree of the mainnet genesis block is the
+	// merkle root committed to by its header.
+	genesis := chaincfg.MainNetParams.GenesisBlock
+	genesisTree := hcutil.NewBlock(genesis).BuildMerkleTree()
+	if len(genesisTree) == 0 ||
+		*genesisTree[len(genesisTree)-1] != genesis.Header.MerkleRoot {
+
+		t.Errorf("BuildMerkleTree (genesis): root does not match header "+
+			"merkle root %v", genesis.Header.MerkleRoot)
+	}
+
+	b := hcutil.NewBlock(&Block100000)
+	var hashes []chainhash.Hash
+	for _, tx := range b.Transactions() {
+		hashes = append(hashes, *tx.WitnessHash())
+	}
+	left := hashPair(hashes[0], hashes[1])
+	right := hashPair(hashes[2], hashes[3])
+	root := hashPair(left, right)
+
+	tree := b.BuildMerkleTree()
+	want := []chainhash.Hash{hashes[0], hashes[1], hashes[2], hashes[3],
+		left, right, root}
+	if len(tree) != len(want) {
+		t.Fatalf("BuildMerkleTree: mismatched length - got %d, want %d",
+			len(tree), len(want))
+	}
+	for i := range want {
+		if tree[i] == nil || *tree[i] != want[i] {
+			t.Errorf("BuildMerkleTree: mismatched node %d - got %v, "+
+				"want %v", i, tree[i], want[i])
+		}
+	}
+
+	// The root must agree with the merkle proofs for the block.
+	proof, err := b.MerkleProof(1)
+	if err != nil {
+		t.Fatalf("MerkleProof: unexpected error: %v", err)
+	}
+	if !hcutil.VerifyMerkleProof(hashes[1], proof, 1, *tree[len(tree)-1]) {
+		t.Errorf("VerifyMerkleProof: proof rejected for tree root")
+	}
+
+	// Ensure the tree of an odd number of transactions pads the leaves
+	// with nil entries and pairs the last transaction with itself.
+	oddMsgBlock := Block100000
+	oddMsgBlock.Transactions = Block100000.Transactions[:3]
+	oddTree := hcutil.NewBlock(&oddMsgBlock).BuildMerkleTree()
+	if len(oddTree) != 7 {
+		t.Fatalf("BuildMerkleTree (odd): mismatched length - got %d, "+
+			"want 7", len(oddTree))
+	}
+	if oddTree[3] != nil {
+		t.Errorf("BuildMerkleTree (odd): padding leaf is not nil")
+	}
+	oddRoot := hashPair(left, hashPair(hashes[2], hashes[2]))
+	if *oddTree[6] != oddRoot {
+		t.Errorf("BuildMerkleTree (odd): mismatched root - got %v, "+
+			"want %v", oddTree[6], oddRoot)
+	}
+
+	// A block with a single transaction has its hash as the root.
+	singleMsgBlock := Block100000
+	singleMsgBlock.Transactions = Block100000.Transactions[:1]
+	singleTree := hcutil.NewBlock(&singleMsgBlock).BuildMerkleTree()
+	if len(singleTree) != 1 || *singleTree[0] != hashes[0] {
+		t.Errorf("BuildMerkleTree (single): mismatched tree - got %v, "+
+			"want [%v]", singleTree, hashes[0])
+	}
+}
+
+// TestBlockHeaderHash ensures the header hash is the hash of the serialized
+// header of a fixture block and is the same as the block hash.
+func TestBlockHeaderHash(t *testing.T) {
+	b := hcutil.NewBlock(&Block100000)
+
+	var buf bytes.Buffer
+	if err := Block100000.Header.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	if buf.Len() != wire.MaxBlockHeaderPayload {
+		t.Fatalf("Serialize: mismatched header size - got %d, want %d",
+			buf.Len(), wire.MaxBlockHeaderPayload)
+	}
+	want := chainhash.HashH(buf.Bytes())
+
+	got := b.HeaderHash()
+	if got != want {
+		t.Errorf("HeaderHash: mismatched hash - got %v, want %v", got,
+			want)
+	}
+	if got != *b.Hash() {
+		t.Errorf("HeaderHash: hash differs from block hash - got %v, "+
+			"want %v", got, b.Hash())
+	}
+	wantStr := "142c5f5b6f868b0e70172b78cea2cff21e6580612b3a360cf6bb2a5976e25ed1"
+	if got.String() != wantStr {
+		t.Errorf("HeaderHash: mismatched hash - got %v, want %v", got,
+			wantStr)
+	}
+}
+
+// TestBlockCheckSize ensures blocks are checked against the maximum size as
+// expected at the size boundary.
+func TestBlockCheckSize(t *testing.T) {
+	// Create a synthetic block with a header and a single transaction.
+	msgBlock := wire.NewMsgBlock(&Block100000.Header)
+	msgBlock.AddTransaction(p2pkhTx())
+	b := hcutil.NewBlock(msgBlock)
+	size := msgBlock.SerializeSize()
+
+	tests := []struct {
+		name    string
+		maxSize int
+		err     error
+	}{
+		{name: "above size", maxSize: size + 1},
+		{name: "at size", maxSize: size},
+		{
+			name:    "below size",
+			maxSize: size - 1,
+			err:     hcutil.ErrBlockTooBig{Size: size, MaxSize: size - 1},
+		},
+	}
+	for _, test := range tests {
+		err := b.CheckSize(test.maxSize)
+		if err != test.err {
+			t.Errorf("%s: mismatched error - got %v, want %v",
+				test.name, err, test.err)
+		}
+	}
+
+	// The serialized bytes must be cached by the size check.
+	serialized, err := b.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: unexpected error: %v", err)
+	}
+	if len(serialized) != size {
+		t.Errorf("Bytes: mismatched size - got %d, want %d",
+			len(serialized), size)
+	}
+}
+
+// TestBlockMarshalJSON ensures the JSON encoding of a block is as expected.
+func TestBlockMarshalJSON(t *testing.T) {
+	b := hcutil.NewBlock(&Block100000)
+	got, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("MarshalJSON: unexpected error: %v", err)
+	}
+
+	want := `{"hash":"142c5f5b6f868b0e70172b78cea2cff21e6580612b3a360cf6bb2a5976e25ed1",` +
+		`"version":1,` +
+		`"previousblockhash":"000000000002d01c1fccc21636b607dfd930d31d01c3a62104612a1719011250",` +
+		`"merkleroot":"f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766",` +
+		`"stakeroot":"0000000000000000000000000000000000000000000000000000000000000000",` +
+		`"votebits":0,"finalstate":"000000000000","voters":0,"freshstake":0,` +
+		`"revocations":0,"poolsize":0,"bits":"1b04864c","sbits":0,` +
+		`"height":100000,"size":0,"time":1293623863,"nonce":274148111,` +
+		`"extradata":"0000000000000000000000000000000000000000000000000000000000000000",` +
+		`"tx":["1cbd9fe1a143a265cc819ff9d8132a7cbc4ca48eb68c0de39cfdf7ecf42cbbd1",` +
+		`"f3f9bc9473b6fe18d66e3ac2a1a95b6317b280f4e6687a074075b56aebf1eb53",` +
+		`"ba2ed6210a561a4dab34ec8668ad61ec97f126826dae893719dff7383b9d6928",` +
+		`"c5dde35b55b856cf73b2d85737c68b0dcfdaad01d0271ee509f3a7efacc025b3"],` +
+		`"stx":[]}`
+	if string(got) != want {
+		t.Errorf("MarshalJSON: mismatched JSON - got:\n%s\nwant:\n%s",
+			got, want)
+	}
+}
+
 // Block100000 defines block 100,000 of the block chain.  It is used to
 // test Block operations.
 var Block100000 = wire.MsgBlock{
@@ -0,0 +1,175 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/crypto/bliss"
+)
+
+// These constants identify the kind of address in the first byte of the
+// compact address serialization.
+const (
+	compactPubKeyHashSecp    byte = 0x00
+	compactPubKeyHashEdwards byte = 0x01
+	compactPubKeyHashSchnorr byte = 0x02
+	compactPubKeyHashBliss   byte = 0x03
+	compactScriptHash        byte = 0x10
+	compactPubKeySecp        byte = 0x20
+	compactPubKeyEdwards     byte = 0x21
+	compactPubKeySchnorr     byte = 0x22
+	compactPubKeyBliss       byte = 0x23
+)
+
+var (
+	// ErrMalformedCompactAddress describes an error where a compact
+	// address serialization is too short or identifies an unknown address
+	// kind or network.
+	ErrMalformedCompactAddress = errors.New("malformed compact address")
+
+	// ErrUnregisteredNet describes an error where an address can not be
+	// serialized in the compact form since it is not for any of the
+	// networks returned by RegisteredNets.
+	ErrUnregisteredNet = errors.New("address is not for a registered " +
+		"network")
+)
+
+// SerializeCompactAddress returns a compact binary serialization of the
+// passed address suitable for internal interfaces which do not need the string
+// encoding.  It is a byte identifying the kind of address, followed by the
+// index of the network of the address in RegisteredNets, followed by the
+// script address.  It may be decoded with DecodeCompactAddress.
+//
+// ErrUnregisteredNet is returned when the address is not for a registered
+// network and ErrUnknownAddressType is returned for addresses of types not
+// defined by this package.
+func SerializeCompactAddress(addr Address) ([]byte, error) {
+	var kind byte
+	switch a := addr.(type) {
+	case *AddressPubKeyHash:
+		kind = compactPubKeyHashKind(a.DSA(a.net))
+	case *AddressScriptHash:
+		kind = compactScriptHash
+	case *AddressSecpPubKey:
+		kind = compactPubKeySecp
+	case *AddressEdwardsPubKey:
+		kind = compactPubKeyEdwards
+	case *AddressSecSchnorrPubKey:
+		kind = compactPubKeySchnorr
+	case *AddressBlissPubKey:
+		kind = compactPubKeyBliss
+	default:
+		return nil, ErrUnknownAddressType
+	}
+
+	net := addr.Net()
+	for i, addrNet := range registeredNets {
+		if addrNet == net && i <= 0xff {
+			scriptAddr := addr.ScriptAddress()
+			b := make([]byte, 0, 2+len(scriptAddr))
+			b = append(b, kind, byte(i))
+			return append(b, scriptAddr...), nil
+		}
+	}
+	return nil, ErrUnregisteredNet
+}
+
+// compactPubKeyHashKind returns the compact address kind for a
+// pay-to-pubkey-hash address with the passed digital signature algorithm.
+func compactPubKeyHashKind(dsa int) byte {
+	switch dsa {
+	case chainec.ECTypeEdwards:
+		return compactPubKeyHashEdwards
+	case chainec.ECTypeSecSchnorr:
+		return compactPubKeyHashSchnorr
+	case bliss.BSTypeBliss:
+		return compactPubKeyHashBliss
+	}
+	return compactPubKeyHashSecp
+}
+
+// DecodeCompactAddress decodes the compact serialization of an address, as
+// returned by SerializeCompactAddress, and returns the Address.
+// ErrMalformedCompactAddress is returned when the serialization is too short
+// or identifies an unknown address kind or network.  The same errors as the
+// constructor of the identified address kind are returned when the script
+// address is invalid.
+func DecodeCompactAddress(b []byte) (Address, error) {
+	if len(b) < 2 || int(b[1]) >= len(registeredNets) {
+		return nil, ErrMalformedCompactAddress
+	}
+	kind, net, scriptAddr := b[0], registeredNets[b[1]], b[2:]
+
+	switch kind {
+	case compactPubKeyHashSecp:
+		return NewAddressPubKeyHash(scriptAddr, net,
+			chainec.ECTypeSecp256k1)
+	case compactPubKeyHashEdwards:
+		return NewAddressPubKeyHash(scriptAddr, net, chainec.ECTypeEdwards)
+	case compactPubKeyHashSchnorr:
+		return NewAddressPubKeyHash(scriptAddr, net,
+			chainec.ECTypeSecSchnorr)
+	case compactPubKeyHashBliss:
+		return NewAddressPubKeyHash(scriptAddr, net, bliss.BSTypeBliss)
+	case compactScriptHash:
+		return NewAddressScriptHashFromHash(scriptAddr, net)
+	case compactPubKeySecp:
+		return NewAddressSecpPubKey(scriptAddr, net)
+	case compactPubKeyEdwards:
+		return NewAddressEdwardsPubKey(scriptAddr, net)
+	case compactPubKeySchnorr:
+		return NewAddressSecSchnorrPubKey(scriptAddr, net)
+	case compactPubKeyBliss:
+		return NewAddressBlissPubKey(scriptAddr, net)
+	}
+	return nil, ErrMalformedCompactAddress
+}
+
+// addressAmountLen is the length of the amount appended to the compact
+// address serialization by SerializeAddressAmount.
+const addressAmountLen = 8
+
+// SerializeAddressAmount returns a compact serialization of the passed address
+// and amount pair, such as for use in invoices.  It is the compact
+// serialization of the address, as returned by SerializeCompactAddress,
+// followed by the amount encoded as an 8 byte little-endian integer.  The same
+// errors as SerializeCompactAddress are returned when the address can not be
+// serialized.
+func SerializeAddressAmount(addr Address, amt Amount) ([]byte, error) {
+	compact, err := SerializeCompactAddress(addr)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, len(compact)+addressAmountLen)
+	copy(b, compact)
+	binary.LittleEndian.PutUint64(b[len(compact):], uint64(amt))
+	return b, nil
+}
+
+// DecodeAddressAmount decodes an address and amount pair serialized with
+// SerializeAddressAmount.  ErrMalformedCompactAddress is returned when the
+// serialization is too short, and an error is returned when the amount is
+// negative or greater than MaxAmount.  The same errors as DecodeCompactAddress
+// are returned when the address is invalid.
+func DecodeAddressAmount(b []byte) (Address, Amount, error) {
+	if len(b) < 2+addressAmountLen {
+		return nil, 0, ErrMalformedCompactAddress
+	}
+	amtOffset := len(b) - addressAmountLen
+	amt := Amount(binary.LittleEndian.Uint64(b[amtOffset:]))
+	if amt < 0 || amt > MaxAmount {
+		return nil, 0, fmt.Errorf("amount %d is outside of the valid "+
+			"range [0, %d]", int64(amt), int64(MaxAmount))
+	}
+	addr, err := DecodeCompactAddress(b[:amtOffset])
+	if err != nil {
+		return nil, 0, err
+	}
+	return addr, amt, nil
+}
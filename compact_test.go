@@ -0,0 +1,258 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"bytes"
+	"testing"
+
+	hcbliss "github.com/HcashOrg/bliss"
+	"github.com/HcashOrg/bliss/sampler"
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/crypto/bliss"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestCompactAddress ensures every address type round trips through the
+// compact serialization and malformed serializations are rejected.
+func TestCompactAddress(t *testing.T) {
+	hash := hexToBytes("1234567890abcdef1234567890abcdef12345678")
+	secpPubKey := hexToBytes("02192d74d0cb94344c9569c2e77901573d8d7903c3e" +
+		"bec3a957724895dca52c6b4")
+
+	// pubKeyBytes returns the compressed serialization of the public key
+	// for a deterministic private key of the passed curve.
+	pubKeyBytes := func(dsa chainec.DSA) []byte {
+		privKey, _ := dsa.PrivKeyFromScalar(bytes.Repeat([]byte{0x01}, 32))
+		x, y := privKey.Public()
+		return dsa.NewPublicKey(x, y).SerializeCompressed()
+	}
+
+	// Generate a deterministic bliss public key.
+	entropy, err := sampler.NewEntropy(bytes.Repeat([]byte{0x01}, 64))
+	if err != nil {
+		t.Fatalf("NewEntropy: unexpected error: %v", err)
+	}
+	blissPrivKey, err := hcbliss.GeneratePrivateKey(1, entropy)
+	if err != nil {
+		t.Fatalf("GeneratePrivateKey: unexpected error: %v", err)
+	}
+	blissPubKey := blissPrivKey.PublicKey().Serialize()
+
+	tests := []struct {
+		name string
+		f    func(net *chaincfg.Params) (hcutil.Address, error)
+	}{
+		{
+			name: "p2pkh secp256k1",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressPubKeyHash(hash, net,
+					chainec.ECTypeSecp256k1)
+			},
+		},
+		{
+			name: "p2pkh edwards",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressPubKeyHash(hash, net,
+					chainec.ECTypeEdwards)
+			},
+		},
+		{
+			name: "p2pkh schnorr",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressPubKeyHash(hash, net,
+					chainec.ECTypeSecSchnorr)
+			},
+		},
+		{
+			name: "p2pkh bliss",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressPubKeyHash(hash, net,
+					bliss.BSTypeBliss)
+			},
+		},
+		{
+			name: "p2sh",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressScriptHashFromHash(hash, net)
+			},
+		},
+		{
+			name: "p2pk secp256k1",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressSecpPubKey(secpPubKey, net)
+			},
+		},
+		{
+			name: "p2pk edwards",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressEdwardsPubKey(
+					pubKeyBytes(chainec.Edwards), net)
+			},
+		},
+		{
+			name: "p2pk schnorr",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressSecSchnorrPubKey(
+					pubKeyBytes(chainec.SecSchnorr), net)
+			},
+		},
+		{
+			name: "p2pk bliss",
+			f: func(net *chaincfg.Params) (hcutil.Address, error) {
+				return hcutil.NewAddressBlissPubKey(blissPubKey, net)
+			},
+		},
+	}
+
+	nets := []*chaincfg.Params{&chaincfg.MainNetParams,
+		&chaincfg.TestNet2Params, &chaincfg.SimNetParams}
+	for _, test := range tests {
+		for _, net := range nets {
+			addr, err := test.f(net)
+			if err != nil {
+				t.Errorf("%s (%s): unexpected error: %v", test.name,
+					net.Name, err)
+				continue
+			}
+			compact, err := hcutil.SerializeCompactAddress(addr)
+			if err != nil {
+				t.Errorf("%s (%s): SerializeCompactAddress: "+
+					"unexpected error: %v", test.name, net.Name, err)
+				continue
+			}
+			decoded, err := hcutil.DecodeCompactAddress(compact)
+			if err != nil {
+				t.Errorf("%s (%s): DecodeCompactAddress: unexpected "+
+					"error: %v", test.name, net.Name, err)
+				continue
+			}
+			if decoded.String() != addr.String() {
+				t.Errorf("%s (%s): mismatched address - got %s, "+
+					"want %s", test.name, net.Name, decoded, addr)
+			}
+			if decoded.DSA(net) != addr.DSA(net) {
+				t.Errorf("%s (%s): mismatched DSA - got %d, want %d",
+					test.name, net.Name, decoded.DSA(net),
+					addr.DSA(net))
+			}
+			if decoded.Net() != net {
+				t.Errorf("%s (%s): decoded address is for the wrong "+
+					"network", test.name, net.Name)
+			}
+		}
+	}
+
+	// Ensure addresses for networks which are not registered are rejected.
+	unregNet := chaincfg.MainNetParams
+	addr, err := hcutil.NewAddressPubKeyHash(hash, &unregNet,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	_, err = hcutil.SerializeCompactAddress(addr)
+	if err != hcutil.ErrUnregisteredNet {
+		t.Errorf("unregistered network: mismatched error - got %v, want "+
+			"%v", err, hcutil.ErrUnregisteredNet)
+	}
+
+	malformed := []struct {
+		name string
+		b    []byte
+	}{
+		{name: "empty", b: nil},
+		{name: "missing network", b: []byte{0x00}},
+		{name: "unknown network", b: append([]byte{0x00, 0xff}, hash...)},
+		{name: "unknown kind", b: append([]byte{0x7f, 0x00}, hash...)},
+	}
+	for _, test := range malformed {
+		_, err := hcutil.DecodeCompactAddress(test.b)
+		if err != hcutil.ErrMalformedCompactAddress {
+			t.Errorf("%s: mismatched error - got %v, want %v",
+				test.name, err, hcutil.ErrMalformedCompactAddress)
+		}
+	}
+
+	// Invalid script addresses are rejected by the address constructors.
+	_, err = hcutil.DecodeCompactAddress([]byte{0x00, 0x00, 0x01})
+	if err == nil {
+		t.Errorf("short hash: expected error")
+	}
+}
+
+// TestAddressAmount ensures address and amount pairs round trip through their
+// compact serialization and malformed serializations are rejected.
+func TestAddressAmount(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := hexToBytes("1234567890abcdef1234567890abcdef12345678")
+	p2pkh, err := hcutil.NewAddressPubKeyHash(hash, net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2sh, err := hcutil.NewAddressScriptHashFromHash(hash, net)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHashFromHash: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		addr hcutil.Address
+		amt  hcutil.Amount
+	}{
+		{name: "p2pkh zero", addr: p2pkh, amt: 0},
+		{name: "p2pkh one coin", addr: p2pkh, amt: hcutil.AtomsPerCoin},
+		{name: "p2sh max amount", addr: p2sh, amt: hcutil.MaxAmount},
+	}
+	for _, test := range tests {
+		b, err := hcutil.SerializeAddressAmount(test.addr, test.amt)
+		if err != nil {
+			t.Errorf("%s: SerializeAddressAmount: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		addr, amt, err := hcutil.DecodeAddressAmount(b)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if addr.String() != test.addr.String() {
+			t.Errorf("%s: mismatched address - got %s, want %s",
+				test.name, addr, test.addr)
+		}
+		if amt != test.amt {
+			t.Errorf("%s: mismatched amount - got %v, want %v",
+				test.name, amt, test.amt)
+		}
+	}
+
+	// Ensure truncated serializations are rejected.
+	b, err := hcutil.SerializeAddressAmount(p2pkh, hcutil.AtomsPerCoin)
+	if err != nil {
+		t.Fatalf("SerializeAddressAmount: unexpected error: %v", err)
+	}
+	_, _, err = hcutil.DecodeAddressAmount(b[:9])
+	if err != hcutil.ErrMalformedCompactAddress {
+		t.Errorf("truncated: mismatched error - got %v, want %v", err,
+			hcutil.ErrMalformedCompactAddress)
+	}
+	if _, _, err := hcutil.DecodeAddressAmount(b[1:]); err == nil {
+		t.Errorf("missing kind: expected error")
+	}
+
+	// Ensure amounts outside of the valid range are rejected.
+	for _, amt := range []hcutil.Amount{-1, hcutil.MaxAmount + 1} {
+		b, err := hcutil.SerializeAddressAmount(p2pkh, amt)
+		if err != nil {
+			t.Errorf("amount %d: SerializeAddressAmount: unexpected "+
+				"error: %v", int64(amt), err)
+			continue
+		}
+		if _, _, err := hcutil.DecodeAddressAmount(b); err == nil {
+			t.Errorf("amount %d: expected error", int64(amt))
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+)
+
+// ConfirmationStatus returns a human-readable label for the passed number of
+// confirmations along with whether or not the coinbase maturity of the network
+// has been reached.  The label is "unconfirmed" when there are no
+// confirmations, "N confirmations" until the coinbase maturity is reached,
+// and "confirmed" afterwards.
+func ConfirmationStatus(confirmations int32, net *chaincfg.Params) (string, bool) {
+	switch {
+	case confirmations <= 0:
+		return "unconfirmed", false
+	case confirmations >= int32(net.CoinbaseMaturity):
+		return "confirmed", true
+	case confirmations == 1:
+		return "1 confirmation", false
+	}
+
+	return strconv.FormatInt(int64(confirmations), 10) + " confirmations",
+		false
+}
+
+// AddressAge returns the age, in confirmations, of an address first seen in
+// the block at firstSeenHeight when the best chain is at currentHeight.  The
+// block the address was first seen in counts as the first confirmation, so an
+// address first seen in the current block has an age of 1.  An error is
+// returned when the current height is less than the first seen height.
+func AddressAge(firstSeenHeight, currentHeight int32) (int32, error) {
+	if currentHeight < firstSeenHeight {
+		return 0, fmt.Errorf("current height %d is less than first seen "+
+			"height %d", currentHeight, firstSeenHeight)
+	}
+	return currentHeight - firstSeenHeight + 1, nil
+}
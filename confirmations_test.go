@@ -0,0 +1,76 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestConfirmationStatus ensures the confirmation labels and finality are
+// reported as expected around the coinbase maturity.
+func TestConfirmationStatus(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	maturity := int32(net.CoinbaseMaturity)
+
+	tests := []struct {
+		name          string
+		confirmations int32
+		wantStatus    string
+		wantFinal     bool
+	}{
+		{"negative", -1, "unconfirmed", false},
+		{"zero", 0, "unconfirmed", false},
+		{"one", 1, "1 confirmation", false},
+		{"partial", 6, "6 confirmations", false},
+		{"one before maturity", maturity - 1,
+			fmt.Sprintf("%d confirmations", maturity-1), false},
+		{"mature", maturity, "confirmed", true},
+		{"past maturity", maturity + 100, "confirmed", true},
+	}
+
+	for _, test := range tests {
+		status, final := hcutil.ConfirmationStatus(test.confirmations, net)
+		if status != test.wantStatus || final != test.wantFinal {
+			t.Errorf("%s: unexpected result - got (%q, %v), want "+
+				"(%q, %v)", test.name, status, final,
+				test.wantStatus, test.wantFinal)
+		}
+	}
+}
+
+// TestAddressAge ensures address ages are calculated in confirmations and
+// inverted heights are rejected.
+func TestAddressAge(t *testing.T) {
+	tests := []struct {
+		name    string
+		first   int32
+		current int32
+		want    int32
+		wantErr bool
+	}{
+		{"genesis", 0, 0, 1, false},
+		{"current block", 100000, 100000, 1, false},
+		{"one block later", 100000, 100001, 2, false},
+		{"many blocks later", 1000, 101000, 100001, false},
+		{"inverted", 100001, 100000, 0, true},
+	}
+
+	for _, test := range tests {
+		age, err := hcutil.AddressAge(test.first, test.current)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: unexpected error result - got %v, want "+
+				"error %v", test.name, err, test.wantErr)
+			continue
+		}
+		if age != test.want {
+			t.Errorf("%s: mismatched age - got %d, want %d",
+				test.name, age, test.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+)
+
+// These constants define the script functions of the watch-only descriptors
+// supported by AddressToDescriptor and ParseAddressDescriptor.
+const (
+	descPubKey     = "pk"
+	descPubKeyHash = "pkh"
+	descScriptHash = "sh"
+)
+
+// AddressToDescriptor returns a watch-only descriptor string for the passed
+// address.  The descriptor is of the form pkh(<hash160>) for secp256k1
+// pay-to-pubkey-hash addresses, sh(<hash160>) for pay-to-script-hash
+// addresses, and pk(<pubkey>) for secp256k1 pay-to-pubkey addresses, where
+// the argument is hex encoded.  Other address types are not supported and
+// result in ErrUnknownAddressType.
+func AddressToDescriptor(addr Address) (string, error) {
+	var function string
+	switch a := addr.(type) {
+	case *AddressPubKeyHash:
+		if a.net == nil || a.DSA(a.net) != chainec.ECTypeSecp256k1 {
+			return "", ErrUnknownAddressType
+		}
+		function = descPubKeyHash
+
+	case *AddressScriptHash:
+		function = descScriptHash
+
+	case *AddressSecpPubKey:
+		function = descPubKey
+
+	default:
+		return "", ErrUnknownAddressType
+	}
+
+	return function + "(" + hex.EncodeToString(addr.ScriptAddress()) + ")",
+		nil
+}
+
+// ParseAddressDescriptor parses a watch-only descriptor string produced by
+// AddressToDescriptor and returns the address it describes for the passed
+// network.
+func ParseAddressDescriptor(desc string, net *chaincfg.Params) (Address, error) {
+	open := strings.IndexByte(desc, '(')
+	if open == -1 || !strings.HasSuffix(desc, ")") {
+		return nil, fmt.Errorf("malformed descriptor %q", desc)
+	}
+	function := desc[:open]
+	data, err := hex.DecodeString(desc[open+1 : len(desc)-1])
+	if err != nil {
+		return nil, fmt.Errorf("malformed descriptor %q: %v", desc, err)
+	}
+
+	switch function {
+	case descPubKeyHash:
+		return NewAddressPubKeyHash(data, net, chainec.ECTypeSecp256k1)
+	case descScriptHash:
+		return NewAddressScriptHashFromHash(data, net)
+	case descPubKey:
+		return NewAddressSecpPubKey(data, net)
+	}
+
+	return nil, fmt.Errorf("unsupported descriptor function %q", function)
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestAddressDescriptors ensures addresses round trip through their
+// watch-only descriptor strings.
+func TestAddressDescriptors(t *testing.T) {
+	tests := []struct {
+		name string
+		addr string
+		desc string
+	}{
+		{
+			name: "mainnet p2pkh",
+			addr: "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+			desc: "pkh(2789d58cfa0957d206f025c2af056fc8a77cebb0)",
+		},
+		{
+			name: "mainnet p2sh",
+			addr: "DcuQKx8BES9wU7C6Q5VmLBjw436r27hayjS",
+			desc: "sh(f0b4e85100aee1a996f22915eb3c3f764d53779a)",
+		},
+	}
+
+	for _, test := range tests {
+		addr, err := hcutil.DecodeAddress(test.addr)
+		if err != nil {
+			t.Errorf("%s: DecodeAddress: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		desc, err := hcutil.AddressToDescriptor(addr)
+		if err != nil {
+			t.Errorf("%s: AddressToDescriptor: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		if desc != test.desc {
+			t.Errorf("%s: mismatched descriptor - got %s, want %s",
+				test.name, desc, test.desc)
+			continue
+		}
+
+		parsed, err := hcutil.ParseAddressDescriptor(desc,
+			&chaincfg.MainNetParams)
+		if err != nil {
+			t.Errorf("%s: ParseAddressDescriptor: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		if parsed.EncodeAddress() != test.addr {
+			t.Errorf("%s: mismatched address - got %s, want %s",
+				test.name, parsed.EncodeAddress(), test.addr)
+		}
+	}
+
+	// Ensure malformed and unsupported descriptors are rejected.
+	badDescs := []string{
+		"pkh2789d58cfa0957d206f025c2af056fc8a77cebb0",
+		"pkh(2789d58cfa0957d206f025c2af056fc8a77cebzz)",
+		"pkh(2789d58cfa0957d206f025c2af056fc8a77ceb)",
+		"wpkh(2789d58cfa0957d206f025c2af056fc8a77cebb0)",
+	}
+	for _, desc := range badDescs {
+		_, err := hcutil.ParseAddressDescriptor(desc,
+			&chaincfg.MainNetParams)
+		if err == nil {
+			t.Errorf("%s: expected error", desc)
+		}
+	}
+}
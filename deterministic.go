@@ -0,0 +1,65 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"encoding/binary"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+)
+
+// deterministicKeyDomain is prepended to the seed of deterministic keys so
+// they can not collide with keys derived from the same integers elsewhere.
+const deterministicKeyDomain = "hcutil deterministic test key"
+
+// deterministicPrivKey returns a secp256k1 private key derived from the
+// blake256 hash of the passed seed.
+func deterministicPrivKey(seed uint64) chainec.PrivateKey {
+	var buf [len(deterministicKeyDomain) + 8]byte
+	copy(buf[:], deterministicKeyDomain)
+	binary.BigEndian.PutUint64(buf[len(deterministicKeyDomain):], seed)
+	privKey, _ := chainec.Secp256k1.PrivKeyFromBytes(chainhash.HashB(buf[:]))
+	return privKey
+}
+
+// DeterministicAddress returns a secp256k1 pay-to-pubkey-hash address for the
+// passed network that is derived from the seed.  The same seed always results
+// in the same address while distinct seeds result in distinct addresses.
+//
+// This is intended to simplify writing tests which need stable addresses and
+// must NOT be used to generate addresses for real funds since the private key
+// is trivially recoverable from the seed.
+func DeterministicAddress(seed uint64, net *chaincfg.Params) *AddressPubKeyHash {
+	pkx, pky := deterministicPrivKey(seed).Public()
+	pubKey := chainec.Secp256k1.NewPublicKey(pkx, pky)
+	addr, err := NewAddressPubKeyHash(Hash160(pubKey.SerializeCompressed()),
+		net, chainec.ECTypeSecp256k1)
+	if err != nil {
+		// The hash is always the correct length, so this can only
+		// happen due to a programming error.
+		panic(err)
+	}
+	return addr
+}
+
+// DeterministicWIF returns the WIF for the passed network of the secp256k1
+// private key derived from the seed.  It pairs with DeterministicAddress such
+// that the pay-to-pubkey-hash address of the compressed public key of the
+// returned WIF is the address DeterministicAddress returns for the same seed.
+//
+// As with DeterministicAddress, this is intended for tests and must NOT be
+// used for real funds.
+func DeterministicWIF(seed uint64, net *chaincfg.Params) *WIF {
+	wif, err := NewWIF(deterministicPrivKey(seed), net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		// NewWIF only fails without a network, so this can only happen
+		// due to a programming error.
+		panic(err)
+	}
+	return wif
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestDeterministicAddress ensures deterministic addresses are stable for a
+// given seed and differ between seeds.
+func TestDeterministicAddress(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	seen := make(map[string]uint64)
+	for seed := uint64(0); seed < 8; seed++ {
+		addr := hcutil.DeterministicAddress(seed, net)
+		again := hcutil.DeterministicAddress(seed, net)
+		if addr.EncodeAddress() != again.EncodeAddress() {
+			t.Errorf("seed %d: address is not deterministic - %v != %v",
+				seed, addr, again)
+		}
+		if !addr.IsForNet(net) {
+			t.Errorf("seed %d: address is not for %s", seed, net.Name)
+		}
+		if other, ok := seen[addr.EncodeAddress()]; ok {
+			t.Errorf("seed %d: address %v matches seed %d", seed, addr,
+				other)
+		}
+		seen[addr.EncodeAddress()] = seed
+
+		// Ensure the address round trips through decoding.
+		if _, err := hcutil.DecodeAddress(addr.EncodeAddress()); err != nil {
+			t.Errorf("seed %d: DecodeAddress: unexpected error: %v",
+				seed, err)
+		}
+	}
+}
+
+// TestDeterministicWIF ensures deterministic WIFs are stable for a given seed
+// and belong to the deterministic address for the same seed.
+func TestDeterministicWIF(t *testing.T) {
+	net := &chaincfg.TestNet2Params
+	for seed := uint64(0); seed < 8; seed++ {
+		wif := hcutil.DeterministicWIF(seed, net)
+		again := hcutil.DeterministicWIF(seed, net)
+		if wif.String() != again.String() {
+			t.Errorf("seed %d: WIF is not deterministic - %v != %v",
+				seed, wif, again)
+		}
+		if !wif.IsForNet(net) {
+			t.Errorf("seed %d: WIF is not for %s", seed, net.Name)
+		}
+
+		addr, err := hcutil.NewAddressPubKeyHash(
+			hcutil.Hash160(wif.SerializePubKey()), net,
+			chainec.ECTypeSecp256k1)
+		if err != nil {
+			t.Errorf("seed %d: NewAddressPubKeyHash: unexpected "+
+				"error: %v", seed, err)
+			continue
+		}
+		want := hcutil.DeterministicAddress(seed, net)
+		if addr.EncodeAddress() != want.EncodeAddress() {
+			t.Errorf("seed %d: mismatched address - got %v, want %v",
+				seed, addr, want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"fmt"
+)
+
+// ErrorCode identifies a kind of error.
+type ErrorCode int
+
+// These constants are used to identify a specific error.
+const (
+	// ErrBelowStakeDifficulty indicates a stake amount is less than the
+	// minimum allowed by the stake difficulty window.
+	ErrBelowStakeDifficulty ErrorCode = iota
+
+	// ErrAboveStakeDifficulty indicates a stake amount is greater than the
+	// maximum allowed by the stake difficulty window.
+	ErrAboveStakeDifficulty
+)
+
+// Map of ErrorCode values back to their constant names for pretty printing.
+var errorCodeStrings = map[ErrorCode]string{
+	ErrBelowStakeDifficulty: "ErrBelowStakeDifficulty",
+	ErrAboveStakeDifficulty: "ErrAboveStakeDifficulty",
+}
+
+// String returns the ErrorCode as a human-readable name.
+func (e ErrorCode) String() string {
+	if s := errorCodeStrings[e]; s != "" {
+		return s
+	}
+	return fmt.Sprintf("Unknown ErrorCode (%d)", int(e))
+}
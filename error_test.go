@@ -0,0 +1,30 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestErrorCodeStringer tests the stringized output for the ErrorCode type.
+func TestErrorCodeStringer(t *testing.T) {
+	tests := []struct {
+		in   hcutil.ErrorCode
+		want string
+	}{
+		{hcutil.ErrBelowStakeDifficulty, "ErrBelowStakeDifficulty"},
+		{hcutil.ErrAboveStakeDifficulty, "ErrAboveStakeDifficulty"},
+		{0xffff, "Unknown ErrorCode (65535)"},
+	}
+	for i, test := range tests {
+		result := test.in.String()
+		if result != test.want {
+			t.Errorf("String #%d - got: %s, want: %s", i, result,
+				test.want)
+		}
+	}
+}
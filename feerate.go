@@ -0,0 +1,102 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"math"
+
+	"github.com/HcashOrg/hcd/wire"
+)
+
+// FeeRate describes a transaction fee rate denominated in atoms per kilobyte
+// of serialized transaction.
+type FeeRate Amount
+
+// Fee returns the fee for a transaction of the passed serialized size in bytes
+// at the fee rate.  Any nonzero rate results in a fee of at least the rate
+// itself so that tiny transactions still pay a nonzero fee.  The result is
+// capped at MaxAmount.
+func (r FeeRate) Fee(size int) Amount {
+	if size > 0 && int64(r) > math.MaxInt64/int64(size) {
+		return MaxAmount
+	}
+
+	fee := Amount(int64(r) * int64(size) / 1000)
+	if fee == 0 && r > 0 {
+		fee = Amount(r)
+	}
+	if fee > MaxAmount {
+		fee = MaxAmount
+	}
+	return fee
+}
+
+// String returns the fee rate formatted as coins per kilobyte.
+func (r FeeRate) String() string {
+	return Amount(r).String() + "/kB"
+}
+
+// Cmp compares the fee rate to other and returns -1, 0, or 1 when the fee rate
+// is less than, equal to, or greater than other, respectively.
+func (r FeeRate) Cmp(other FeeRate) int {
+	switch {
+	case r < other:
+		return -1
+	case r > other:
+		return 1
+	}
+	return 0
+}
+
+// IsReasonable returns whether or not the fee rate is within the inclusive
+// range [minRelay, maxSane].  Rates below the minimum relay fee rate will not
+// be relayed, while rates above the maximum sane fee rate likely indicate the
+// user is about to overpay by mistake.
+func (r FeeRate) IsReasonable(minRelay, maxSane FeeRate) bool {
+	return r.Cmp(minRelay) >= 0 && r.Cmp(maxSane) <= 0
+}
+
+// EffectiveFeeRate returns the fee rate the transaction pays when paying the
+// passed fee, based on its full serialized size.  It is the inverse of
+// FeeRate.Fee, so calculating the fee for the serialized size at the returned
+// rate results in the passed fee to within rounding.  The result is capped at
+// MaxAmount per kilobyte.
+func (t *Tx) EffectiveFeeRate(fee Amount) FeeRate {
+	size := int64(t.msgTx.SerializeSize())
+	if fee > math.MaxInt64/1000 {
+		return FeeRate(MaxAmount)
+	}
+	rate := int64(fee) * 1000 / size
+	if rate > MaxAmount {
+		rate = MaxAmount
+	}
+	return FeeRate(rate)
+}
+
+// MinRelayFee returns the minimum fee the passed transaction must pay to be
+// relayed at the provided relay fee rate, based on its full serialized size.
+func MinRelayFee(tx *wire.MsgTx, relayFeeRate FeeRate) Amount {
+	return relayFeeRate.Fee(tx.SerializeSize())
+}
+
+// MeetsRelayFee returns whether or not the paid fee is at least the minimum
+// relay fee for the passed transaction at the provided relay fee rate.
+func MeetsRelayFee(tx *wire.MsgTx, paidFee Amount, relayFeeRate FeeRate) bool {
+	return paidFee >= MinRelayFee(tx, relayFeeRate)
+}
+
+// MinReplacementFee returns the minimum fee a replacement for a transaction
+// which paid the passed original fee must pay to be relayed, where the
+// replacement has the passed serialized size.  It is the original fee plus the
+// relay fee for the replacement at the provided relay fee rate, so the
+// replacement pays for its own relay in addition to that of the original.  The
+// result is capped at MaxAmount.
+func MinReplacementFee(originalFee Amount, newSize int, relayFeeRate FeeRate) Amount {
+	incrementalFee := relayFeeRate.Fee(newSize)
+	if originalFee > MaxAmount-incrementalFee {
+		return MaxAmount
+	}
+	return originalFee + incrementalFee
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcutil"
+)
+
+// p2pkhTx returns a typical transaction spending a single pay-to-pubkey-hash
+// output to two pay-to-pubkey-hash outputs.
+func p2pkhTx() *wire.MsgTx {
+	pkScript := append([]byte{0x76, 0xa9, 0x14}, bytes.Repeat([]byte{0x01}, 20)...)
+	pkScript = append(pkScript, 0x88, 0xac)
+
+	// A signature script consisting of a 72 byte signature and a 33 byte
+	// compressed public key.
+	sigScript := append([]byte{0x48}, bytes.Repeat([]byte{0x02}, 72)...)
+	sigScript = append(sigScript, 0x21)
+	sigScript = append(sigScript, bytes.Repeat([]byte{0x03}, 33)...)
+
+	tx := wire.NewMsgTx()
+	prevOut := wire.NewOutPoint(&chainhash.Hash{0x01}, 0, wire.TxTreeRegular)
+	tx.AddTxIn(wire.NewTxIn(prevOut, 100000000, sigScript))
+	tx.AddTxOut(wire.NewTxOut(60000000, pkScript))
+	tx.AddTxOut(wire.NewTxOut(39990000, pkScript))
+	return tx
+}
+
+// TestFeeRateFee ensures fees are calculated from fee rates as expected.
+func TestFeeRateFee(t *testing.T) {
+	tests := []struct {
+		name string
+		rate hcutil.FeeRate
+		size int
+		want hcutil.Amount
+	}{
+		{"zero rate", 0, 250, 0},
+		{"exact kilobyte", 1e5, 1000, 1e5},
+		{"partial kilobyte", 1e5, 250, 25000},
+		{"truncated", 1e3, 1999, 1999},
+		{"tiny tx pays rate", 1e2, 5, 1e2},
+		{"capped", hcutil.FeeRate(hcutil.MaxAmount), 2000, hcutil.MaxAmount},
+	}
+
+	for _, test := range tests {
+		if got := test.rate.Fee(test.size); got != test.want {
+			t.Errorf("%s: unexpected fee - got %v, want %v", test.name,
+				int64(got), int64(test.want))
+		}
+	}
+}
+
+// TestMinRelayFee ensures the minimum relay fee and the relay fee threshold
+// are calculated as expected for a typical transaction.
+func TestMinRelayFee(t *testing.T) {
+	tx := p2pkhTx()
+	const wantSize = 252
+	if size := tx.SerializeSize(); size != wantSize {
+		t.Fatalf("unexpected transaction size - got %d, want %d", size,
+			wantSize)
+	}
+
+	const relayFeeRate = hcutil.FeeRate(1e5)
+	const wantFee = hcutil.Amount(25200)
+	fee := hcutil.MinRelayFee(tx, relayFeeRate)
+	if fee != wantFee {
+		t.Fatalf("unexpected minimum relay fee - got %v, want %v",
+			int64(fee), int64(wantFee))
+	}
+
+	tests := []struct {
+		name    string
+		paidFee hcutil.Amount
+		want    bool
+	}{
+		{"below threshold", wantFee - 1, false},
+		{"at threshold", wantFee, true},
+		{"above threshold", wantFee + 1, true},
+	}
+	for _, test := range tests {
+		got := hcutil.MeetsRelayFee(tx, test.paidFee, relayFeeRate)
+		if got != test.want {
+			t.Errorf("%s: unexpected result - got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestMinReplacementFee ensures the minimum fee of a replacement transaction
+// covers the original fee plus the relay fee for the replacement.
+func TestMinReplacementFee(t *testing.T) {
+	tests := []struct {
+		name        string
+		originalFee hcutil.Amount
+		newSize     int
+		rate        hcutil.FeeRate
+		want        hcutil.Amount
+	}{
+		{"zero rate", 25200, 252, 0, 25200},
+		{"same size", 25200, 252, 1e5, 50400},
+		{"larger replacement", 25200, 1000, 1e5, 125200},
+		{"zero original fee", 0, 500, 1e4, 5000},
+		{"tiny replacement pays rate", 1000, 5, 1e2, 1100},
+		{"capped", hcutil.MaxAmount - 1, 1000, 1e5, hcutil.MaxAmount},
+	}
+
+	for _, test := range tests {
+		got := hcutil.MinReplacementFee(test.originalFee, test.newSize,
+			test.rate)
+		if got != test.want {
+			t.Errorf("%s: unexpected fee - got %v, want %v", test.name,
+				int64(got), int64(test.want))
+		}
+	}
+}
+
+// TestFeeRateIsReasonable ensures fee rates are compared and checked against
+// sane bounds as expected.
+func TestFeeRateIsReasonable(t *testing.T) {
+	const (
+		minRelay = hcutil.FeeRate(1e4)
+		maxSane  = hcutil.FeeRate(1e7)
+	)
+	tests := []struct {
+		name string
+		rate hcutil.FeeRate
+		want bool
+	}{
+		{"zero", 0, false},
+		{"below min", minRelay - 1, false},
+		{"at min", minRelay, true},
+		{"within", 1e5, true},
+		{"at max", maxSane, true},
+		{"above max", maxSane + 1, false},
+	}
+	for _, test := range tests {
+		got := test.rate.IsReasonable(minRelay, maxSane)
+		if got != test.want {
+			t.Errorf("%s: unexpected result - got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+
+	cmpTests := []struct {
+		a, b hcutil.FeeRate
+		want int
+	}{
+		{1, 2, -1},
+		{2, 2, 0},
+		{3, 2, 1},
+	}
+	for _, test := range cmpTests {
+		if got := test.a.Cmp(test.b); got != test.want {
+			t.Errorf("Cmp(%d, %d): unexpected result - got %d, want %d",
+				test.a, test.b, got, test.want)
+		}
+	}
+}
+
+// TestTxEffectiveFeeRate ensures the effective fee rate of a transaction is
+// the inverse of calculating the fee from a fee rate.
+func TestTxEffectiveFeeRate(t *testing.T) {
+	tx := hcutil.NewTx(p2pkhTx())
+	size := tx.MsgTx().SerializeSize()
+
+	tests := []struct {
+		name string
+		fee  hcutil.Amount
+		want hcutil.FeeRate
+	}{
+		{"zero fee", 0, 0},
+		{"exact rate", 25200, 1e5},
+		{"rounded down rate", 25250, 100198},
+		{"huge fee", hcutil.MaxAmount, hcutil.MaxAmount},
+	}
+	for _, test := range tests {
+		rate := tx.EffectiveFeeRate(test.fee)
+		if rate != test.want {
+			t.Errorf("%s: unexpected fee rate - got %d, want %d",
+				test.name, int64(rate), int64(test.want))
+			continue
+		}
+
+		// Calculating the fee at the effective rate must result in the
+		// original fee to within the rounding of a single atom per
+		// kilobyte.
+		if test.fee == 0 || test.fee == hcutil.MaxAmount {
+			continue
+		}
+		fee := rate.Fee(size)
+		if fee > test.fee || test.fee-fee > hcutil.Amount(size)/1000+1 {
+			t.Errorf("%s: fee does not round trip - got %d, want %d",
+				test.name, int64(fee), int64(test.fee))
+		}
+	}
+}
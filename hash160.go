@@ -7,6 +7,7 @@
 package hcutil
 
 import (
+	"errors"
 	"hash"
 
 	"golang.org/x/crypto/ripemd160"
@@ -24,3 +25,24 @@ func calcHash(buf []byte, hasher hash.Hash) []byte {
 func Hash160(buf []byte) []byte {
 	return calcHash(chainhash.HashB(buf), ripemd160.New())
 }
+
+// PubKeyHashFromCompressed calculates the hash ripemd160(hash256(b)) of the
+// passed 33-byte compressed secp256k1 public key.  It produces the same hash as
+// the pay-to-pubkey-hash address of the public key without constructing the
+// address or parsing the public key, so it is suitable for scanning large
+// numbers of keys.  Only the length and format byte of the key are checked.
+func PubKeyHashFromCompressed(compressed []byte) ([ripemd160.Size]byte, error) {
+	var pkHash [ripemd160.Size]byte
+	if len(compressed) != 33 {
+		return pkHash, errors.New("compressed public key must be 33 bytes")
+	}
+	if compressed[0] != 0x02 && compressed[0] != 0x03 {
+		return pkHash, errors.New("invalid compressed public key format")
+	}
+
+	h := chainhash.HashH(compressed)
+	hasher := ripemd160.New()
+	hasher.Write(h[:])
+	hasher.Sum(pkHash[:0])
+	return pkHash, nil
+}
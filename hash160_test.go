@@ -0,0 +1,71 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcutil"
+)
+
+// compressedPubKey is a compressed secp256k1 public key used by the tests.
+var compressedPubKey = hexToBytes("02192d74d0cb94344c9569c2e77901573d8d" +
+	"7903c3ebec3a957724895dca52c6b4")
+
+// TestPubKeyHashFromCompressed ensures the hash calculated from a compressed
+// public key matches the hash of its pay-to-pubkey-hash address.
+func TestPubKeyHashFromCompressed(t *testing.T) {
+	addr, err := hcutil.NewAddressSecpPubKey(compressedPubKey,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressSecpPubKey: unexpected error: %v", err)
+	}
+	want := addr.AddressPubKeyHash().Hash160()
+
+	pkHash, err := hcutil.PubKeyHashFromCompressed(compressedPubKey)
+	if err != nil {
+		t.Fatalf("PubKeyHashFromCompressed: unexpected error: %v", err)
+	}
+	if pkHash != *want {
+		t.Errorf("PubKeyHashFromCompressed: mismatched hash - got %x, "+
+			"want %x", pkHash, *want)
+	}
+
+	// Keys which are not of the compressed form are rejected.
+	uncompressed := append([]byte{0x04}, compressedPubKey[1:]...)
+	tests := [][]byte{
+		nil,
+		compressedPubKey[:32],
+		append(append([]byte{}, compressedPubKey...), 0x00),
+		uncompressed,
+	}
+	for _, key := range tests {
+		if _, err := hcutil.PubKeyHashFromCompressed(key); err == nil {
+			t.Errorf("PubKeyHashFromCompressed: expected error for "+
+				"key %x", key)
+		}
+	}
+}
+
+// BenchmarkPubKeyHashFromCompressed benchmarks calculating the hash of a
+// compressed public key without constructing its address.
+func BenchmarkPubKeyHashFromCompressed(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		hcutil.PubKeyHashFromCompressed(compressedPubKey)
+	}
+}
+
+// BenchmarkPubKeyHashFromAddress benchmarks calculating the hash of a
+// compressed public key by constructing its address for comparison.
+func BenchmarkPubKeyHashFromAddress(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		addr, _ := hcutil.NewAddressSecpPubKey(compressedPubKey,
+			&chaincfg.MainNetParams)
+		addr.AddressPubKeyHash().Hash160()
+	}
+}
@@ -9,6 +9,7 @@ package hdkeychain_test
 import (
 	"testing"
 
+	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcutil/hdkeychain"
 )
 
@@ -84,3 +85,23 @@ func BenchmarkSerialize(b *testing.B) {
 		masterKey.String()
 	}
 }
+
+// BenchmarkAddressAtIndex benchmarks how long it takes to derive the address
+// of a normal (non-hardened) child from a master public extended key.
+func BenchmarkAddressAtIndex(b *testing.B) {
+	b.StopTimer()
+	masterKey, err := hdkeychain.NewKeyFromString(bip0032MasterPriv1)
+	if err != nil {
+		b.Errorf("Failed to decode master seed: %v", err)
+	}
+	pubKey, err := masterKey.Neuter()
+	if err != nil {
+		b.Errorf("Failed to neuter master key: %v", err)
+	}
+	net := &chaincfg.MainNetParams
+	b.StartTimer()
+
+	for i := 0; i < b.N; i++ {
+		pubKey.AddressAtIndex(uint32(i%1000), net)
+	}
+}
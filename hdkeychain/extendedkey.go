@@ -43,6 +43,15 @@ const (
 	// for hardened child keys is [2^31, 2^32 - 1].
 	HardenedKeyStart = 0x80000000 // 2^31
 
+	// ExternalBranch is the child index of an account extended key used by
+	// convention for the branch that derives externally visible (receiving)
+	// addresses.
+	ExternalBranch = 0
+
+	// InternalBranch is the child index of an account extended key used by
+	// convention for the branch that derives internal (change) addresses.
+	InternalBranch = 1
+
 	// MinSeedBytes is the minimum number of bytes allowed for a seed to
 	// a master node.
 	MinSeedBytes = 16 // 128 bits
@@ -106,6 +115,23 @@ var (
 	// key is not the expected length.
 	ErrInvalidKeyLen = errors.New("the provided serialized extended key " +
 		"length is invalid")
+
+	// ErrChainFingerprintMismatch describes an error in which an extended
+	// key in a derivation chain does not reference the preceding key as its
+	// parent.
+	ErrChainFingerprintMismatch = errors.New("extended key parent " +
+		"fingerprint does not match the preceding key")
+
+	// ErrChainDepthMismatch describes an error in which an extended key in a
+	// derivation chain is not exactly one level deeper than the preceding
+	// key.
+	ErrChainDepthMismatch = errors.New("extended key depth does not follow " +
+		"the preceding key")
+
+	// ErrUnknownKeyNet describes an error in which the version bytes of a
+	// serialized extended key do not identify any known network.
+	ErrUnknownKeyNet = errors.New("extended key version does not match " +
+		"any known network")
 )
 
 // masterKey is the master key used along with a random seed used to generate
@@ -196,6 +222,15 @@ func (k *ExtendedKey) ParentFingerprint() uint32 {
 	return binary.BigEndian.Uint32(k.parentFP)
 }
 
+// Fingerprint returns the fingerprint of the extended key, which is the first 4
+// bytes of the RIPEMD160(BLAKE256(pubKey)) of its public key interpreted as a
+// big-endian uint32.  It is the value returned by ParentFingerprint for the
+// children derived from the key, so it may be used to identify a wallet by its
+// master key.
+func (k *ExtendedKey) Fingerprint() uint32 {
+	return binary.BigEndian.Uint32(hcutil.Hash160(k.pubKeyBytes())[:4])
+}
+
 // Child returns a derived child extended key at the given index.  When this
 // extended key is a private extended key (as determined by the IsPrivate
 // function), a private extended key will be derived.  Otherwise, the derived
@@ -432,6 +467,94 @@ func (k *ExtendedKey) Address(net *chaincfg.Params, addrtype uint8) (*hcutil.Add
 	return hcutil.NewAddressPubKeyHash(pkHash, net, chainec.ECTypeSecp256k1)
 }
 
+// AddressAtIndex returns the pay-to-pubkey-hash address of the child extended
+// key at the passed index for the passed network.  This is equivalent to
+// calling Child followed by Address on the result, however the intermediate
+// child key is never handed back to the caller, which makes it well suited for
+// generating large batches of addresses from a branch extended key.
+//
+// The same errors as Child are returned.  In particular, ErrInvalidChild is
+// returned in the extremely rare case the index does not derive to a usable
+// child, and the caller is expected to simply increment to the next index.
+func (k *ExtendedKey) AddressAtIndex(index uint32, net *chaincfg.Params) (*hcutil.AddressPubKeyHash, error) {
+	child, err := k.Child(index)
+	if err != nil {
+		return nil, err
+	}
+	return child.Address(net, child.algtype)
+}
+
+// IsInternalBranch returns whether the passed branch index is the internal
+// (change) branch by convention.
+func IsInternalBranch(branch uint32) bool {
+	return branch == InternalBranch
+}
+
+// DeriveBranchAddress returns the pay-to-pubkey-hash address at the passed
+// index of the passed branch of an account extended key for the passed
+// network.  The branch is typically ExternalBranch or InternalBranch.
+//
+// The same errors as Child are returned for either level of derivation.
+func DeriveBranchAddress(acctKey *ExtendedKey, branch, index uint32, net *chaincfg.Params) (*hcutil.AddressPubKeyHash, error) {
+	branchKey, err := acctKey.Child(branch)
+	if err != nil {
+		return nil, err
+	}
+	return branchKey.AddressAtIndex(index, net)
+}
+
+// ForEachAddress derives the pay-to-pubkey-hash addresses of the passed branch
+// of the extended key for the passed network on demand, starting at the passed
+// index, and calls fn with each index and address in turn.  Iteration stops
+// when fn returns true for stop or a non-nil error, in which case the error is
+// returned.  The key is typically an account extended key and the branch is
+// typically ExternalBranch or InternalBranch.
+//
+// Indices for which the derived key is invalid are skipped, as recommended by
+// BIP0032, so fn may not be called with every index.  Iteration also stops
+// once the last non-hardened index has been visited.  The same errors as Child
+// are returned when the branch can not be derived.
+func (k *ExtendedKey) ForEachAddress(branch, start uint32, net *chaincfg.Params,
+	fn func(index uint32, addr *hcutil.AddressPubKeyHash) (stop bool, err error)) error {
+
+	branchKey, err := k.Child(branch)
+	if err != nil {
+		return err
+	}
+
+	for index := start; index < HardenedKeyStart; index++ {
+		addr, err := branchKey.AddressAtIndex(index, net)
+		if err == ErrInvalidChild {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		stop, err := fn(index, addr)
+		if err != nil || stop {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyDerivedAddress returns whether the pay-to-pubkey-hash address at the
+// passed index of the passed branch of an extended public key matches the
+// expected address for the passed network.  It is intended for auditing that
+// an account extended public key generates the expected receive addresses.
+//
+// An error describing the failed branch and index is returned in the rare
+// case that either level of derivation produces an invalid child.
+func VerifyDerivedAddress(xpub *ExtendedKey, branch, index uint32, expected hcutil.Address, net *chaincfg.Params) (bool, error) {
+	addr, err := DeriveBranchAddress(xpub, branch, index, net)
+	if err != nil {
+		return false, fmt.Errorf("unable to derive address for branch "+
+			"%d index %d: %v", branch, index, err)
+	}
+	return addr.EncodeAddress() == expected.EncodeAddress(), nil
+}
+
 // paddedAppend appends the src byte slice to dst, returning the new slice.
 // If the length of the source is smaller than the passed size, leading zero
 // bytes are appended to the dst slice before appending src.
@@ -491,6 +614,26 @@ func (k *ExtendedKey) IsForNet(net *chaincfg.Params) bool {
 		bytes.Equal(k.version, net.HDPublicKeyID[:])
 }
 
+// ExtendedKeyNetwork decodes the passed base58-encoded extended key and returns
+// the network identified by its version bytes along with whether or not it is
+// a private extended key.  The same errors as NewKeyFromString are returned
+// when the key can not be decoded, and ErrUnknownKeyNet is returned when the
+// version bytes do not identify any of the networks returned by
+// hcutil.RegisteredNets.
+func ExtendedKeyNetwork(s string) (*chaincfg.Params, bool, error) {
+	key, err := NewKeyFromString(s)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, net := range hcutil.RegisteredNets() {
+		if key.IsForNet(net) {
+			return net, key.IsPrivate(), nil
+		}
+	}
+	return nil, false, ErrUnknownKeyNet
+}
+
 // SetNet associates the extended key, and any child keys yet to be derived from
 // it, with the passed network.
 func (k *ExtendedKey) SetNet(net *chaincfg.Params) {
@@ -535,6 +678,29 @@ func (k *ExtendedKey) SetAlgType(i uint8) {
 	k.algtype = i
 }
 
+// VerifyChain ensures the passed extended keys form a single derivation chain
+// in which every key is a direct child of the key preceding it.  That is to
+// say the parent fingerprint of each key must match the fingerprint of its
+// predecessor and its depth must be exactly one more than its predecessor.
+//
+// ErrChainFingerprintMismatch or ErrChainDepthMismatch is returned for the
+// first link that does not satisfy these requirements.  An empty chain or a
+// chain consisting of a single key is always considered valid.
+func VerifyChain(keys []*ExtendedKey) error {
+	for i := 1; i < len(keys); i++ {
+		parent, child := keys[i-1], keys[i]
+		if child.depth != parent.depth+1 {
+			return ErrChainDepthMismatch
+		}
+
+		if child.ParentFingerprint() != parent.Fingerprint() {
+			return ErrChainFingerprintMismatch
+		}
+	}
+
+	return nil
+}
+
 // NewMaster creates a new master node for use in creating a hierarchical
 // deterministic key chain.  The seed must be between 128 and 512 bits and
 // should be generated by a cryptographically secure random generation source.
@@ -18,6 +18,7 @@ import (
 	"testing"
 
 	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcutil"
 	"github.com/HcashOrg/hcutil/hdkeychain"
 )
 
@@ -981,3 +982,457 @@ func TestZero(t *testing.T) {
 		}
 	}
 }
+
+// TestAddressAtIndex ensures deriving an address directly from an extended key
+// produces the same address as deriving the child and then its address.
+func TestAddressAtIndex(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatalf("DecodeString: unexpected error: %v", err)
+	}
+	master, err := hdkeychain.NewMaster(seed, net)
+	if err != nil {
+		t.Fatalf("NewMaster: unexpected error: %v", err)
+	}
+	pubMaster, err := master.Neuter()
+	if err != nil {
+		t.Fatalf("Neuter: unexpected error: %v", err)
+	}
+
+	keys := []struct {
+		name string
+		key  *hdkeychain.ExtendedKey
+	}{
+		{name: "private", key: master},
+		{name: "public", key: pubMaster},
+	}
+	for _, test := range keys {
+		for i := uint32(0); i < 5; i++ {
+			got, err := test.key.AddressAtIndex(i, net)
+			if err != nil {
+				t.Errorf("AddressAtIndex (%s) #%d: unexpected "+
+					"error: %v", test.name, i, err)
+				continue
+			}
+
+			child, err := test.key.Child(i)
+			if err != nil {
+				t.Errorf("Child (%s) #%d: unexpected error: %v",
+					test.name, i, err)
+				continue
+			}
+			want, err := child.Address(net, child.GetAlgType())
+			if err != nil {
+				t.Errorf("Address (%s) #%d: unexpected error: %v",
+					test.name, i, err)
+				continue
+			}
+
+			if got.EncodeAddress() != want.EncodeAddress() {
+				t.Errorf("AddressAtIndex (%s) #%d: mismatched "+
+					"address -- got %s, want %s", test.name, i,
+					got.EncodeAddress(), want.EncodeAddress())
+			}
+		}
+	}
+
+	// Deriving a hardened address from a public key must fail the same way
+	// deriving the hardened child does.
+	_, err = pubMaster.AddressAtIndex(hdkeychain.HardenedKeyStart, net)
+	if err != hdkeychain.ErrDeriveHardFromPublic {
+		t.Errorf("AddressAtIndex: mismatched error -- got: %v, want: %v",
+			err, hdkeychain.ErrDeriveHardFromPublic)
+	}
+}
+
+// TestVerifyChain ensures derivation chains are validated as intended.
+func TestVerifyChain(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	seed1, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	seed2, _ := hex.DecodeString("fffcf9f6f3f0edeae7e4e1dedbd8d5d2")
+	master1, err := hdkeychain.NewMaster(seed1, net)
+	if err != nil {
+		t.Fatalf("NewMaster: unexpected error: %v", err)
+	}
+	master2, err := hdkeychain.NewMaster(seed2, net)
+	if err != nil {
+		t.Fatalf("NewMaster: unexpected error: %v", err)
+	}
+
+	// Derive m/0H/1 from both masters.
+	acct1, err := master1.Child(hdkeychain.HardenedKeyStart)
+	if err != nil {
+		t.Fatalf("Child: unexpected error: %v", err)
+	}
+	branch1, err := acct1.Child(1)
+	if err != nil {
+		t.Fatalf("Child: unexpected error: %v", err)
+	}
+	acct2, err := master2.Child(hdkeychain.HardenedKeyStart)
+	if err != nil {
+		t.Fatalf("Child: unexpected error: %v", err)
+	}
+	branch2, err := acct2.Child(1)
+	if err != nil {
+		t.Fatalf("Child: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		keys []*hdkeychain.ExtendedKey
+		err  error
+	}{
+		{
+			name: "empty chain",
+			keys: nil,
+		},
+		{
+			name: "single key",
+			keys: []*hdkeychain.ExtendedKey{master1},
+		},
+		{
+			name: "valid 3-level chain",
+			keys: []*hdkeychain.ExtendedKey{master1, acct1, branch1},
+		},
+		{
+			name: "mismatched parent fingerprint",
+			keys: []*hdkeychain.ExtendedKey{master1, acct1, branch2},
+			err:  hdkeychain.ErrChainFingerprintMismatch,
+		},
+		{
+			name: "skipped level",
+			keys: []*hdkeychain.ExtendedKey{master1, branch1},
+			err:  hdkeychain.ErrChainDepthMismatch,
+		},
+		{
+			name: "reversed order",
+			keys: []*hdkeychain.ExtendedKey{acct2, master2},
+			err:  hdkeychain.ErrChainDepthMismatch,
+		},
+	}
+
+	for i, test := range tests {
+		err := hdkeychain.VerifyChain(test.keys)
+		if err != test.err {
+			t.Errorf("VerifyChain #%d (%s): mismatched error -- "+
+				"got: %v, want: %v", i, test.name, err, test.err)
+		}
+	}
+}
+
+// TestDeriveBranchAddress ensures addresses derived from the external and
+// internal branches of an account match manual derivation and differ from each
+// other.
+func TestDeriveBranchAddress(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatalf("DecodeString: unexpected error: %v", err)
+	}
+	master, err := hdkeychain.NewMaster(seed, net)
+	if err != nil {
+		t.Fatalf("NewMaster: unexpected error: %v", err)
+	}
+	acct, err := master.Child(hdkeychain.HardenedKeyStart)
+	if err != nil {
+		t.Fatalf("Child: unexpected error: %v", err)
+	}
+
+	if hdkeychain.IsInternalBranch(hdkeychain.ExternalBranch) {
+		t.Errorf("IsInternalBranch: external branch reported as internal")
+	}
+	if !hdkeychain.IsInternalBranch(hdkeychain.InternalBranch) {
+		t.Errorf("IsInternalBranch: internal branch not reported as " +
+			"internal")
+	}
+
+	for i := uint32(0); i < 3; i++ {
+		ext, err := hdkeychain.DeriveBranchAddress(acct,
+			hdkeychain.ExternalBranch, i, net)
+		if err != nil {
+			t.Fatalf("DeriveBranchAddress (external) #%d: unexpected "+
+				"error: %v", i, err)
+		}
+		intl, err := hdkeychain.DeriveBranchAddress(acct,
+			hdkeychain.InternalBranch, i, net)
+		if err != nil {
+			t.Fatalf("DeriveBranchAddress (internal) #%d: unexpected "+
+				"error: %v", i, err)
+		}
+		if ext.EncodeAddress() == intl.EncodeAddress() {
+			t.Errorf("DeriveBranchAddress #%d: external and internal "+
+				"addresses match: %s", i, ext.EncodeAddress())
+		}
+
+		branchKey, err := acct.Child(hdkeychain.ExternalBranch)
+		if err != nil {
+			t.Fatalf("Child: unexpected error: %v", err)
+		}
+		want, err := branchKey.AddressAtIndex(i, net)
+		if err != nil {
+			t.Fatalf("AddressAtIndex: unexpected error: %v", err)
+		}
+		if ext.EncodeAddress() != want.EncodeAddress() {
+			t.Errorf("DeriveBranchAddress #%d: mismatched address -- "+
+				"got %s, want %s", i, ext.EncodeAddress(),
+				want.EncodeAddress())
+		}
+	}
+}
+
+// TestVerifyDerivedAddress ensures VerifyDerivedAddress accepts the address
+// derived from a known extended public key and rejects mismatched ones.
+func TestVerifyDerivedAddress(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	xpub, err := hdkeychain.NewKeyFromString("dpubZ9169KDAEUnyoBhjjmT2VaEodr6pUTDoqCEAeqgbfr2JfkB88BbK77jbTYbcYXb2FVz7DKBdW4P618yd51MwF8DjKVopSbS7Lkgi6bowX5w")
+	if err != nil {
+		t.Fatalf("NewKeyFromString: unexpected error: %v", err)
+	}
+
+	want, err := hcutil.DecodeAddress("Dsny5ivo9i4ur6QC3rUtVwURPxm9KvHmjsJ")
+	if err != nil {
+		t.Fatalf("DecodeAddress: unexpected error: %v", err)
+	}
+	ok, err := hdkeychain.VerifyDerivedAddress(xpub,
+		hdkeychain.ExternalBranch, 1, want, net)
+	if err != nil {
+		t.Fatalf("VerifyDerivedAddress: unexpected error: %v", err)
+	}
+	if !ok {
+		t.Errorf("VerifyDerivedAddress: expected address %s not verified",
+			want.EncodeAddress())
+	}
+
+	// The same address must not verify at a different index or branch.
+	ok, err = hdkeychain.VerifyDerivedAddress(xpub,
+		hdkeychain.ExternalBranch, 2, want, net)
+	if err != nil {
+		t.Fatalf("VerifyDerivedAddress: unexpected error: %v", err)
+	}
+	if ok {
+		t.Errorf("VerifyDerivedAddress: mismatched index verified")
+	}
+	ok, err = hdkeychain.VerifyDerivedAddress(xpub,
+		hdkeychain.InternalBranch, 1, want, net)
+	if err != nil {
+		t.Fatalf("VerifyDerivedAddress: unexpected error: %v", err)
+	}
+	if ok {
+		t.Errorf("VerifyDerivedAddress: mismatched branch verified")
+	}
+
+	// Hardened derivation from a public key must fail with an error.
+	_, err = hdkeychain.VerifyDerivedAddress(xpub,
+		hdkeychain.HardenedKeyStart, 1, want, net)
+	if err == nil {
+		t.Errorf("VerifyDerivedAddress: expected error deriving " +
+			"hardened branch from public key")
+	}
+}
+
+// TestFingerprint ensures the fingerprint of an extended key is the parent
+// fingerprint of its children for both private and public derivation.
+func TestFingerprint(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatalf("DecodeString: unexpected error: %v", err)
+	}
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewMaster: unexpected error: %v", err)
+	}
+
+	// The master key has no parent.
+	if fp := master.ParentFingerprint(); fp != 0 {
+		t.Errorf("ParentFingerprint: master key has nonzero parent "+
+			"fingerprint %08x", fp)
+	}
+
+	parent := master
+	for _, index := range []uint32{hdkeychain.HardenedKeyStart, 1, 2} {
+		child, err := parent.Child(index)
+		if err != nil {
+			t.Fatalf("Child(%d): unexpected error: %v", index, err)
+		}
+		if child.ParentFingerprint() != parent.Fingerprint() {
+			t.Errorf("Child(%d): mismatched parent fingerprint - got "+
+				"%08x, want %08x", index, child.ParentFingerprint(),
+				parent.Fingerprint())
+		}
+		if child.Fingerprint() == parent.Fingerprint() {
+			t.Errorf("Child(%d): fingerprint matches parent", index)
+		}
+
+		// The public key has the same fingerprint as the private key,
+		// so children derived from it share the parent fingerprint.
+		pub, err := parent.Neuter()
+		if err != nil {
+			t.Fatalf("Neuter: unexpected error: %v", err)
+		}
+		if pub.Fingerprint() != parent.Fingerprint() {
+			t.Errorf("Neuter: mismatched fingerprint - got %08x, "+
+				"want %08x", pub.Fingerprint(), parent.Fingerprint())
+		}
+		parent = child
+	}
+}
+
+// TestForEachAddress ensures addresses are derived for sequential indices from
+// the start index until the callback stops iteration.
+func TestForEachAddress(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	xpub, err := hdkeychain.NewKeyFromString("dpubZ9169KDAEUnyoBhjjmT2VaEod" +
+		"r6pUTDoqCEAeqgbfr2JfkB88BbK77jbTYbcYXb2FVz7DKBdW4P618yd51MwF8Dj" +
+		"KVopSbS7Lkgi6bowX5w")
+	if err != nil {
+		t.Fatalf("NewKeyFromString: unexpected error: %v", err)
+	}
+
+	const start, count = 5, 4
+	var indices []uint32
+	err = xpub.ForEachAddress(hdkeychain.ExternalBranch, start, net,
+		func(index uint32, addr *hcutil.AddressPubKeyHash) (bool, error) {
+			want, err := hdkeychain.DeriveBranchAddress(xpub,
+				hdkeychain.ExternalBranch, index, net)
+			if err != nil {
+				t.Fatalf("DeriveBranchAddress: unexpected error: %v",
+					err)
+			}
+			if addr.EncodeAddress() != want.EncodeAddress() {
+				t.Errorf("index %d: mismatched address - got %s, "+
+					"want %s", index, addr.EncodeAddress(),
+					want.EncodeAddress())
+			}
+			indices = append(indices, index)
+			return len(indices) == count, nil
+		})
+	if err != nil {
+		t.Fatalf("ForEachAddress: unexpected error: %v", err)
+	}
+	want := []uint32{5, 6, 7, 8}
+	if !reflect.DeepEqual(indices, want) {
+		t.Errorf("ForEachAddress: mismatched indices - got %v, want %v",
+			indices, want)
+	}
+
+	// Ensure callback errors stop iteration and are returned.
+	errStop := errors.New("stop")
+	calls := 0
+	err = xpub.ForEachAddress(hdkeychain.InternalBranch, 0, net,
+		func(uint32, *hcutil.AddressPubKeyHash) (bool, error) {
+			calls++
+			return false, errStop
+		})
+	if err != errStop || calls != 1 {
+		t.Errorf("ForEachAddress: mismatched result - got (%v, %d "+
+			"calls), want (%v, 1 call)", err, calls, errStop)
+	}
+
+	// Iteration ends after the last non-hardened index.
+	calls = 0
+	err = xpub.ForEachAddress(hdkeychain.ExternalBranch,
+		hdkeychain.HardenedKeyStart-2, net,
+		func(uint32, *hcutil.AddressPubKeyHash) (bool, error) {
+			calls++
+			return false, nil
+		})
+	if err != nil || calls != 2 {
+		t.Errorf("ForEachAddress: mismatched result at end of range - "+
+			"got (%v, %d calls), want (<nil>, 2 calls)", err, calls)
+	}
+
+	// Hardened branches can't be derived from a public key.
+	err = xpub.ForEachAddress(hdkeychain.HardenedKeyStart, 0, net,
+		func(uint32, *hcutil.AddressPubKeyHash) (bool, error) {
+			t.Errorf("callback called for underivable branch")
+			return true, nil
+		})
+	if err != hdkeychain.ErrDeriveHardFromPublic {
+		t.Errorf("ForEachAddress: mismatched error - got %v, want %v",
+			err, hdkeychain.ErrDeriveHardFromPublic)
+	}
+}
+
+// TestExtendedKeyNetwork ensures the network and key type of serialized
+// extended keys are identified from their version bytes.
+func TestExtendedKeyNetwork(t *testing.T) {
+	tests := []struct {
+		name    string
+		key     string
+		net     *chaincfg.Params
+		private bool
+		err     error
+	}{
+		{
+			name:    "mainnet private",
+			key:     "dprv3hCznBesA6jBtmoyVFPfyMSZ1qYZ3WdjdebquvkEfmRfxC9VFEFi2YDaJqHnx7uGe75eGSa3Mn3oHK11hBW7KZUrPxwbCPBmuCi1nwm182s",
+			net:     &chaincfg.MainNetParams,
+			private: true,
+		},
+		{
+			name:    "testnet public",
+			key:     "tpubVhnMyQmZAhoosedBTX7oacwyCNc5qtdEMoNHudUCW1R6WZTvqCZQoNJHSn4H11puwdk4qyDv2ET637EDap4r8HH3odjBC5nEjmnPcsDfLwm",
+			net:     &chaincfg.TestNet2Params,
+			private: false,
+		},
+		{
+			name: "bad checksum",
+			key:  "dprv3hCznBesA6jBtmoyVFPfyMSZ1qYZ3WdjdebquvkEfmRfxC9VFEFi2YDaJqHnx7uGe75eGSa3Mn3oHK11hBW7KZUrPxwbCPBmuCi1nwm182t",
+			err:  hdkeychain.ErrBadChecksum,
+		},
+	}
+
+	for _, test := range tests {
+		net, private, err := hdkeychain.ExtendedKeyNetwork(test.key)
+		if err != test.err {
+			t.Errorf("%s: mismatched error - got %v, want %v",
+				test.name, err, test.err)
+			continue
+		}
+		if net != test.net {
+			t.Errorf("%s: mismatched network - got %v, want %v",
+				test.name, net, test.net)
+		}
+		if private != test.private {
+			t.Errorf("%s: mismatched private flag - got %v, want %v",
+				test.name, private, test.private)
+		}
+	}
+
+	// Keys with version bytes that do not belong to any known network are
+	// rejected.
+	key, err := hdkeychain.NewKeyFromString(tests[0].key)
+	if err != nil {
+		t.Fatalf("NewKeyFromString: unexpected error: %v", err)
+	}
+	key.SetNet(&chaincfg.Params{
+		HDPrivateKeyID: [4]byte{0x01, 0x02, 0x03, 0x04},
+		HDPublicKeyID:  [4]byte{0x05, 0x06, 0x07, 0x08},
+	})
+	unknown, err := key.String()
+	if err != nil {
+		t.Fatalf("String: unexpected error: %v", err)
+	}
+	_, _, err = hdkeychain.ExtendedKeyNetwork(unknown)
+	if err != hdkeychain.ErrUnknownKeyNet {
+		t.Errorf("ExtendedKeyNetwork: mismatched error - got %v, want %v",
+			err, hdkeychain.ErrUnknownKeyNet)
+	}
+
+	// Keys for networks registered with hcutil.RegisterNet are recognized.
+	regNet := chaincfg.SimNetParams
+	regNet.Name = "regnet"
+	regNet.Net = 0x12345678
+	regNet.HDPrivateKeyID = [4]byte{0x01, 0x02, 0x03, 0x04}
+	regNet.HDPublicKeyID = [4]byte{0x05, 0x06, 0x07, 0x08}
+	if err := hcutil.RegisterNet(&regNet); err != nil {
+		t.Fatalf("RegisterNet: unexpected error: %v", err)
+	}
+	net, private, err := hdkeychain.ExtendedKeyNetwork(unknown)
+	if err != nil || net != &regNet || !private {
+		t.Errorf("ExtendedKeyNetwork: mismatched result for registered "+
+			"network - got (%v, %v, %v), want (%v, true, <nil>)", net,
+			private, err, &regNet)
+	}
+}
@@ -14,6 +14,7 @@ interface. The functions are only exported while the tests are being run.
 package hcutil
 
 import (
+	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainec"
 	"github.com/HcashOrg/hcutil/base58"
 
@@ -33,6 +34,18 @@ func TstAppDataDir(goos, appName string, roaming bool) string {
 	return appDataDir(goos, appName, roaming)
 }
 
+// TstEnsureDir makes the internal ensureDir function available to the test
+// package.
+func TstEnsureDir(dir string) error {
+	return ensureDir(dir)
+}
+
+// TstSipHash24 makes the internal sipHash24 function available to the test
+// package.
+func TstSipHash24(k0, k1 uint64, b []byte) uint64 {
+	return sipHash24(k0, k1, b)
+}
+
 // TstAddressPubKeyHash makes an AddressPubKeyHash, setting the
 // unexported fields with the parameters hash and netID.
 func TstAddressPubKeyHash(hash [ripemd160.Size]byte,
@@ -67,9 +80,34 @@ func TstAddressPubKey(serializedPubKey []byte, pubKeyFormat PubKeyFormat,
 	}
 }
 
+// TstAddressEdwardsPubKey makes an AddressEdwardsPubKey, setting the
+// unexported fields with the parameters.
+func TstAddressEdwardsPubKey(serializedPubKey []byte,
+	netID [2]byte) *AddressEdwardsPubKey {
+
+	pubKey, _ := chainec.Edwards.ParsePubKey(serializedPubKey)
+	return &AddressEdwardsPubKey{
+		pubKey:       pubKey,
+		pubKeyHashID: netID,
+	}
+}
+
 // TstAddressSAddr returns the expected script address bytes for
 // P2PKH and P2SH hcd addresses.
 func TstAddressSAddr(addr string) []byte {
 	decoded := base58.Decode(addr)
 	return decoded[2 : 2+ripemd160.Size]
 }
+
+// TstAddRegisteredNet adds the passed network to the networks which are
+// checked when the network is not known ahead of time without registering it
+// with the chaincfg package.  The returned function restores the original
+// networks.
+func TstAddRegisteredNet(net *chaincfg.Params) func() {
+	orig := registeredNets
+	n := len(orig)
+	registeredNets = append(orig[:n:n], net)
+	return func() {
+		registeredNets = orig
+	}
+}
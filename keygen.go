@@ -0,0 +1,54 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+)
+
+// GenerateKeyPairs returns n freshly generated secp256k1 private keys encoded
+// as WIFs for the passed network along with the pay-to-pubkey-hash address of
+// the compressed public key of each.  The returned slices are parallel, so the
+// address at each index belongs to the WIF at the same index.  The keys are
+// generated with crypto/rand.
+func GenerateKeyPairs(n int, net *chaincfg.Params) ([]*WIF, []*AddressPubKeyHash, error) {
+	if n <= 0 {
+		return nil, nil, fmt.Errorf("number of key pairs %d must be "+
+			"positive", n)
+	}
+
+	wifs := make([]*WIF, 0, n)
+	addrs := make([]*AddressPubKeyHash, 0, n)
+	for i := 0; i < n; i++ {
+		privKeyBytes, _, _, err := chainec.Secp256k1.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		privKey, _ := chainec.Secp256k1.PrivKeyFromBytes(privKeyBytes)
+		wif, err := NewWIF(privKey, net, chainec.ECTypeSecp256k1)
+		if err != nil {
+			return nil, nil, err
+		}
+		addr, err := NewAddressPubKeyHash(Hash160(wif.SerializePubKey()),
+			net, chainec.ECTypeSecp256k1)
+		if err != nil {
+			return nil, nil, err
+		}
+		wifs = append(wifs, wif)
+		addrs = append(addrs, addr)
+	}
+	return wifs, addrs, nil
+}
+
+// GenerateFaucetKeys returns n freshly generated key pairs for the test network
+// (TestNet2Params) suitable for funding wallets in integration test harnesses.
+// It is otherwise identical to GenerateKeyPairs.
+func GenerateFaucetKeys(n int) ([]*WIF, []*AddressPubKeyHash, error) {
+	return GenerateKeyPairs(n, &chaincfg.TestNet2Params)
+}
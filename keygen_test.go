@@ -0,0 +1,105 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestGenerateKeyPairs ensures the requested number of distinct key pairs is
+// generated and the results are usable.
+func TestGenerateKeyPairs(t *testing.T) {
+	const n = 10
+	net := &chaincfg.TestNet2Params
+	wifs, addrs, err := hcutil.GenerateKeyPairs(n, net)
+	if err != nil {
+		t.Fatalf("GenerateKeyPairs: unexpected error: %v", err)
+	}
+	if len(wifs) != n || len(addrs) != n {
+		t.Fatalf("GenerateKeyPairs: mismatched lengths - got %d WIFs and "+
+			"%d addresses, want %d", len(wifs), len(addrs), n)
+	}
+
+	seen := make(map[string]struct{}, n)
+	for i, addr := range addrs {
+		encoded := addr.EncodeAddress()
+		if _, ok := seen[encoded]; ok {
+			t.Errorf("#%d: duplicate address %s", i, encoded)
+		}
+		seen[encoded] = struct{}{}
+
+		decoded, err := hcutil.DecodeAddress(encoded)
+		if err != nil {
+			t.Errorf("#%d: DecodeAddress: unexpected error: %v", i, err)
+			continue
+		}
+		if !decoded.IsForNet(net) {
+			t.Errorf("#%d: address %s is not for %s", i, encoded,
+				net.Name)
+		}
+
+		// The WIF must round trip and belong to the address.
+		wif, err := hcutil.DecodeWIF(wifs[i].String())
+		if err != nil {
+			t.Errorf("#%d: DecodeWIF: unexpected error: %v", i, err)
+			continue
+		}
+		if !wif.IsForNet(net) {
+			t.Errorf("#%d: WIF is not for %s", i, net.Name)
+		}
+		wifHash := hcutil.Hash160(wif.SerializePubKey())
+		if !bytes.Equal(wifHash, addr.ScriptAddress()) {
+			t.Errorf("#%d: WIF does not belong to address %s", i,
+				encoded)
+		}
+	}
+
+	// Ensure non-positive counts are rejected.
+	for _, n := range []int{0, -1} {
+		if _, _, err := hcutil.GenerateKeyPairs(n, net); err == nil {
+			t.Errorf("GenerateKeyPairs(%d): expected error", n)
+		}
+	}
+}
+
+// TestGenerateFaucetKeys ensures the generated faucet keys are distinct and
+// for the test network.
+func TestGenerateFaucetKeys(t *testing.T) {
+	const n = 5
+	net := &chaincfg.TestNet2Params
+	wifs, addrs, err := hcutil.GenerateFaucetKeys(n)
+	if err != nil {
+		t.Fatalf("GenerateFaucetKeys: unexpected error: %v", err)
+	}
+	if len(wifs) != n || len(addrs) != n {
+		t.Fatalf("GenerateFaucetKeys: mismatched lengths - got %d WIFs "+
+			"and %d addresses, want %d", len(wifs), len(addrs), n)
+	}
+
+	seen := make(map[string]struct{}, n)
+	for i, addr := range addrs {
+		encoded := addr.EncodeAddress()
+		if _, ok := seen[encoded]; ok {
+			t.Errorf("#%d: duplicate address %s", i, encoded)
+		}
+		seen[encoded] = struct{}{}
+
+		if !addr.IsForNet(net) || addr.IsForNet(&chaincfg.MainNetParams) {
+			t.Errorf("#%d: address %s is not only for %s", i, encoded,
+				net.Name)
+		}
+		if !wifs[i].IsForNet(net) {
+			t.Errorf("#%d: WIF is not for %s", i, net.Name)
+		}
+	}
+
+	if _, _, err := hcutil.GenerateFaucetKeys(0); err == nil {
+		t.Errorf("GenerateFaucetKeys(0): expected error")
+	}
+}
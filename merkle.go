@@ -0,0 +1,128 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+)
+
+// hashMerkleBranches returns the hash of the concatenation of the passed left
+// and right child hashes of a merkle tree node.  It matches the hashing done by
+// the blockchain package when calculating merkle roots, which can't be
+// imported here.
+func hashMerkleBranches(left, right *chainhash.Hash) chainhash.Hash {
+	var buf [chainhash.HashSize * 2]byte
+	copy(buf[:chainhash.HashSize], left[:])
+	copy(buf[chainhash.HashSize:], right[:])
+	return chainhash.HashH(buf[:])
+}
+
+// nextPowerOfTwo returns the next highest power of two from the passed number
+// if it is not already a power of two.
+func nextPowerOfTwo(n int) int {
+	if n&(n-1) == 0 {
+		return n
+	}
+	exponent := uint(0)
+	for n > 0 {
+		n >>= 1
+		exponent++
+	}
+	return 1 << exponent
+}
+
+// buildMerkleTreeStore returns the merkle tree built from the passed leaves as
+// a linear array in the same form as the blockchain package.  The leaves come
+// first, padded with nil entries up to the next power of two, followed by each
+// level of internal nodes in turn, so the root is the final element.  A node
+// with a nil right child is the hash of its left child concatenated with
+// itself, and a node with no children is nil.
+func buildMerkleTreeStore(leaves []chainhash.Hash) []*chainhash.Hash {
+	if len(leaves) == 0 {
+		return nil
+	}
+
+	arraySize := nextPowerOfTwo(len(leaves))*2 - 1
+	merkles := make([]*chainhash.Hash, arraySize)
+	for i := range leaves {
+		leaf := leaves[i]
+		merkles[i] = &leaf
+	}
+
+	// Start the array offset after the last leaf and adjusted to the next
+	// power of two.
+	offset := nextPowerOfTwo(len(leaves))
+	for i := 0; i < arraySize-1; i += 2 {
+		switch {
+		// When there is no left child node, the parent is nil too.
+		case merkles[i] == nil:
+			merkles[offset] = nil
+
+		// When there is no right child, the parent is generated by
+		// hashing the concatenation of the left child with itself.
+		case merkles[i+1] == nil:
+			newHash := hashMerkleBranches(merkles[i], merkles[i])
+			merkles[offset] = &newHash
+
+		// The normal case sets the parent node to the hash of the
+		// concatenation of the left and right children.
+		default:
+			newHash := hashMerkleBranches(merkles[i], merkles[i+1])
+			merkles[offset] = &newHash
+		}
+		offset++
+	}
+	return merkles
+}
+
+// merkleProof returns the sibling hashes along the path from the leaf at the
+// passed index to the root of the merkle tree built from the passed leaves.
+// As with merkle roots calculated by the blockchain package, the last node of
+// any level with an odd number of nodes is paired with itself.  The index must
+// be in range.
+func merkleProof(leaves []chainhash.Hash, index int) []chainhash.Hash {
+	level := make([]chainhash.Hash, len(leaves))
+	copy(level, leaves)
+
+	var proof []chainhash.Hash
+	for len(level) > 1 {
+		if len(level)%2 != 0 {
+			level = append(level, level[len(level)-1])
+		}
+		proof = append(proof, level[index^1])
+
+		next := make([]chainhash.Hash, len(level)/2)
+		for i := range next {
+			next[i] = hashMerkleBranches(&level[i*2], &level[i*2+1])
+		}
+		level = next
+		index /= 2
+	}
+	return proof
+}
+
+// VerifyMerkleProof returns whether or not the passed proof, as produced by
+// Block.MerkleProof, shows the transaction with the passed hash is at the
+// passed index of the transaction tree with the passed merkle root.  The hash
+// must be the full hash of the transaction, as returned by Tx.WitnessHash,
+// since that is what the merkle root of a block header commits to.
+func VerifyMerkleProof(txHash chainhash.Hash, proof []chainhash.Hash,
+	index int, root chainhash.Hash) bool {
+
+	if index < 0 || (len(proof) < 63 && index >= 1<<uint(len(proof))) {
+		return false
+	}
+
+	hash := txHash
+	for i := range proof {
+		if index&1 == 0 {
+			hash = hashMerkleBranches(&hash, &proof[i])
+		} else {
+			hash = hashMerkleBranches(&proof[i], &hash)
+		}
+		index >>= 1
+	}
+	return hash == root
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// These are the marker lines which delimit the sections of an armored signed
+// message.
+const (
+	armorBeginMessage   = "-----BEGIN HC SIGNED MESSAGE-----"
+	armorBeginSignature = "-----BEGIN SIGNATURE-----"
+	armorEndMessage     = "-----END HC SIGNED MESSAGE-----"
+)
+
+// ErrMalformedArmor describes an error where an armored signed message is
+// missing marker lines or does not have the expected sections.
+var ErrMalformedArmor = errors.New("malformed armored signed message")
+
+// ArmorSignedMessage returns the passed message along with the address and
+// signature which sign it in a clearsigned style format suitable for sharing
+// as text.  The format is:
+//
+//	-----BEGIN HC SIGNED MESSAGE-----
+//	<message>
+//	-----BEGIN SIGNATURE-----
+//	<address>
+//	<base64 signature>
+//	-----END HC SIGNED MESSAGE-----
+//
+// The message is included verbatim and may span multiple lines.
+func ArmorSignedMessage(addr Address, message string, sig []byte) string {
+	return armorBeginMessage + "\n" +
+		message + "\n" +
+		armorBeginSignature + "\n" +
+		addr.EncodeAddress() + "\n" +
+		base64.StdEncoding.EncodeToString(sig) + "\n" +
+		armorEndMessage + "\n"
+}
+
+// ParseArmoredMessage returns the address string, message, and signature from
+// text produced by ArmorSignedMessage.  Whitespace surrounding the armored
+// block is ignored.  ErrMalformedArmor is returned when the text does not
+// have the expected marker lines and sections, and an error is returned when
+// the signature is not valid base64.
+//
+// The address is not decoded and the signature is not verified.
+func ParseArmoredMessage(text string) (string, string, []byte, error) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, armorBeginMessage+"\n") ||
+		!strings.HasSuffix(text, "\n"+armorEndMessage) {
+
+		return "", "", nil, ErrMalformedArmor
+	}
+	body := text[len(armorBeginMessage)+1 : len(text)-len(armorEndMessage)-1]
+
+	// The message may itself contain any text, so the signature section
+	// begins at the last signature marker line.
+	sigMarker := "\n" + armorBeginSignature + "\n"
+	i := strings.LastIndex(body, sigMarker)
+	if i < 0 {
+		return "", "", nil, ErrMalformedArmor
+	}
+	message, sigSection := body[:i], body[i+len(sigMarker):]
+
+	lines := strings.Split(sigSection, "\n")
+	if len(lines) != 2 || lines[0] == "" || lines[1] == "" {
+		return "", "", nil, ErrMalformedArmor
+	}
+	sig, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	return lines[0], message, sig, nil
+}
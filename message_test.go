@@ -0,0 +1,95 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestArmoredMessage ensures signed messages round trip through the armor
+// format and malformed armor is rejected.
+func TestArmoredMessage(t *testing.T) {
+	addr, err := hcutil.DecodeAddress("DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu")
+	if err != nil {
+		t.Fatalf("DecodeAddress: unexpected error: %v", err)
+	}
+	if !addr.IsForNet(&chaincfg.MainNetParams) {
+		t.Fatalf("DecodeAddress: address is not for mainnet")
+	}
+	sig := bytes.Repeat([]byte{0x1f, 0xa5}, 33)
+
+	messages := []string{
+		"hello world",
+		"",
+		"multiple\nlines\n",
+		"-----BEGIN SIGNATURE-----\nnot the real signature",
+	}
+	for _, message := range messages {
+		armored := hcutil.ArmorSignedMessage(addr, message, sig)
+		gotAddr, gotMessage, gotSig, err := hcutil.ParseArmoredMessage(
+			"\n  " + armored + "\n")
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", message, err)
+			continue
+		}
+		if gotAddr != addr.EncodeAddress() {
+			t.Errorf("%q: mismatched address - got %s, want %s",
+				message, gotAddr, addr.EncodeAddress())
+		}
+		if gotMessage != message {
+			t.Errorf("%q: mismatched message - got %q", message,
+				gotMessage)
+		}
+		if !bytes.Equal(gotSig, sig) {
+			t.Errorf("%q: mismatched signature - got %x, want %x",
+				message, gotSig, sig)
+		}
+	}
+
+	armored := hcutil.ArmorSignedMessage(addr, "hello world", sig)
+	malformed := []struct {
+		name string
+		text string
+	}{
+		{
+			name: "empty",
+			text: "",
+		},
+		{
+			name: "missing begin marker",
+			text: strings.Replace(armored, "BEGIN HC SIGNED", "BEGIN", 1),
+		},
+		{
+			name: "missing end marker",
+			text: strings.Replace(armored, "-----END HC SIGNED MESSAGE-----",
+				"", 1),
+		},
+		{
+			name: "missing signature marker",
+			text: strings.Replace(armored, "-----BEGIN SIGNATURE-----\n",
+				"", 1),
+		},
+		{
+			name: "missing signature",
+			text: strings.Replace(armored, "\n"+addr.EncodeAddress(), "",
+				1),
+		},
+		{
+			name: "invalid base64",
+			text: strings.Replace(armored, "H6Uf", "!!!!", 1),
+		},
+	}
+	for _, test := range malformed {
+		_, _, _, err := hcutil.ParseArmoredMessage(test.text)
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import "fmt"
+
+// CalcInputValueAge returns the sum of each input value multiplied by its age
+// in blocks, where the age of an input is the number of blocks between the
+// height of the block that contains it and the passed height of the next
+// block.  Inputs at or above the next height, such as those which are not yet
+// mined, contribute nothing.
+//
+// An error is returned when the number of input values and input heights
+// differ.
+func CalcInputValueAge(inputValues []Amount, inputHeights []int32, nextHeight int32) (float64, error) {
+	if len(inputValues) != len(inputHeights) {
+		return 0, fmt.Errorf("mismatched input values and heights "+
+			"(%d values, %d heights)", len(inputValues),
+			len(inputHeights))
+	}
+
+	var totalValueAge float64
+	for i, value := range inputValues {
+		inputAge := nextHeight - inputHeights[i]
+		if inputAge > 0 {
+			totalValueAge += float64(value) * float64(inputAge)
+		}
+	}
+	return totalValueAge, nil
+}
+
+// TxPriority returns the legacy priority of a transaction with the passed
+// input value age, as calculated by CalcInputValueAge, and serialized size in
+// bytes.  A zero priority is returned for non-positive sizes.
+func TxPriority(valueAge float64, serializeSize int) float64 {
+	if serializeSize <= 0 {
+		return 0
+	}
+	return valueAge / float64(serializeSize)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestTxPriority ensures input value ages and transaction priorities are
+// calculated as expected.
+func TestTxPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []hcutil.Amount
+		heights  []int32
+		next     int32
+		size     int
+		valueAge float64
+		priority float64
+	}{
+		{
+			name:     "no inputs",
+			next:     100,
+			size:     250,
+			valueAge: 0,
+			priority: 0,
+		},
+		{
+			name:     "single input",
+			values:   []hcutil.Amount{100000000},
+			heights:  []int32{90},
+			next:     100,
+			size:     250,
+			valueAge: 1e9,
+			priority: 4e6,
+		},
+		{
+			name:     "multiple inputs",
+			values:   []hcutil.Amount{50000000, 25000000},
+			heights:  []int32{1, 51},
+			next:     101,
+			size:     500,
+			valueAge: 5e9 + 1.25e9,
+			priority: 1.25e7,
+		},
+		{
+			name:     "unmined input ignored",
+			values:   []hcutil.Amount{100000000, 200000000},
+			heights:  []int32{99, 100},
+			next:     100,
+			size:     100,
+			valueAge: 1e8,
+			priority: 1e6,
+		},
+		{
+			name:     "zero size",
+			values:   []hcutil.Amount{100000000},
+			heights:  []int32{99},
+			next:     100,
+			size:     0,
+			valueAge: 1e8,
+			priority: 0,
+		},
+	}
+
+	for _, test := range tests {
+		valueAge, err := hcutil.CalcInputValueAge(test.values,
+			test.heights, test.next)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if valueAge != test.valueAge {
+			t.Errorf("%s: mismatched value age -- got %v, want %v",
+				test.name, valueAge, test.valueAge)
+			continue
+		}
+		priority := hcutil.TxPriority(valueAge, test.size)
+		if priority != test.priority {
+			t.Errorf("%s: mismatched priority -- got %v, want %v",
+				test.name, priority, test.priority)
+		}
+	}
+
+	// Ensure mismatched slice lengths are rejected.
+	_, err := hcutil.CalcInputValueAge([]hcutil.Amount{1, 2}, []int32{1}, 10)
+	if err == nil {
+		t.Errorf("mismatched lengths: expected error")
+	}
+}
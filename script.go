@@ -0,0 +1,1021 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/crypto/bliss"
+	"github.com/HcashOrg/hcd/wire"
+)
+
+// These are the opcodes used when building and inspecting the standard
+// scripts handled by this package.  They mirror the values defined by the
+// txscript package, which can not be imported here since it depends on this
+// package.
+const (
+	opFalse         = 0x00
+	opData1         = 0x01
+	opData20        = 0x14
+	opData75        = 0x4b
+	opPushData1     = 0x4c
+	opPushData2     = 0x4d
+	opPushData4     = 0x4e
+	op1Negate       = 0x4f
+	opReserved      = 0x50
+	op1             = 0x51
+	op16            = 0x60
+	opReturn        = 0x6a
+	opDup           = 0x76
+	opEqual         = 0x87
+	opEqualVerify   = 0x88
+	opHash160       = 0xa9
+	opCodeSeparator = 0xab
+	opCheckSig      = 0xac
+	opCheckMultiSig = 0xae
+	opSStx          = 0xba
+	opSSGen         = 0xbb
+	opSSRtx         = 0xbc
+	opSStxChange    = 0xbd
+	opCheckSigAlt   = 0xbe
+
+	// The verify variants of the signature checking opcodes are only
+	// needed to count signature operations.
+	opCheckSigVerify      = 0xad
+	opCheckMultiSigVerify = 0xaf
+	opCheckSigAltVerify   = 0xbf
+)
+
+// maxDataCarrierSize is the maximum number of bytes allowed in pushed data
+// to be considered a standard null data script.
+const maxDataCarrierSize = 256
+
+// maxPubKeysPerMultiSig is the maximum number of public keys allowed in a
+// standard multi-signature script.
+const maxPubKeysPerMultiSig = op16 - op1 + 1
+
+const (
+	// maxSecpSigLen is the maximum length of a DER encoded secp256k1
+	// signature.
+	maxSecpSigLen = 72
+
+	// altSigLen is the length of an Ed25519 or secp256k1 Schnorr signature.
+	altSigLen = 64
+)
+
+// SignatureScriptSize returns the size of the signature script which redeems a
+// pay-to-pubkey-hash output for a key of the passed signature algorithm, for
+// use in fee estimation.  The script consists of a data push of the signature
+// followed by its 1 byte hash type, and a data push of the serialized public
+// key.  The compressed flag selects between the compressed and uncompressed
+// serialization of secp256k1 public keys and is ignored for other algorithms.
+//
+// Since the length of a DER encoded secp256k1 signature varies, the size for
+// secp256k1 keys is based on the maximum signature length, which results in a
+// size of 108 bytes for compressed and 140 bytes for uncompressed public keys.
+// Zero is returned for unknown algorithms and for BLISS, which has variable
+// length signatures.
+func SignatureScriptSize(algo int, compressed bool) int {
+	var sigLen, pubKeyLen int
+	switch algo {
+	case chainec.ECTypeSecp256k1:
+		sigLen = maxSecpSigLen
+		pubKeyLen = chainec.Secp256k1.PubKeyBytesLenUncompressed()
+		if compressed {
+			pubKeyLen = chainec.Secp256k1.PubKeyBytesLenCompressed()
+		}
+	case chainec.ECTypeEdwards:
+		sigLen = altSigLen
+		pubKeyLen = chainec.Edwards.PubKeyBytesLen()
+	case chainec.ECTypeSecSchnorr:
+		sigLen = altSigLen
+		pubKeyLen = chainec.SecSchnorr.PubKeyBytesLen()
+	default:
+		return 0
+	}
+
+	// OP_DATA_N <signature> <hash type> OP_DATA_N <public key>
+	return 1 + sigLen + 1 + 1 + pubKeyLen
+}
+
+// addScriptData appends the passed data to the script using the smallest
+// possible push operation and returns the resulting script.
+func addScriptData(script, data []byte) []byte {
+	dataLen := len(data)
+
+	// When the data consists of a single number that can be represented
+	// by one of the "small integer" opcodes, use that opcode instead of a
+	// data push opcode followed by the number.
+	if dataLen == 0 || (dataLen == 1 && data[0] == 0) {
+		return append(script, opFalse)
+	} else if dataLen == 1 && data[0] <= 16 {
+		return append(script, op1-1+data[0])
+	} else if dataLen == 1 && data[0] == 0x81 {
+		return append(script, op1Negate)
+	}
+
+	// Use one of the OP_DATA_# opcodes if the length of the data is small
+	// enough so the data push instruction is only a single byte.
+	// Otherwise, choose the smallest possible OP_PUSHDATA# opcode that can
+	// represent the length of the data.
+	switch {
+	case dataLen <= opData75:
+		script = append(script, byte(opData1-1+dataLen))
+	case dataLen <= 0xff:
+		script = append(script, opPushData1, byte(dataLen))
+	case dataLen <= 0xffff:
+		var buf [2]byte
+		binary.LittleEndian.PutUint16(buf[:], uint16(dataLen))
+		script = append(script, opPushData2)
+		script = append(script, buf[:]...)
+	default:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], uint32(dataLen))
+		script = append(script, opPushData4)
+		script = append(script, buf[:]...)
+	}
+
+	return append(script, data...)
+}
+
+// multiSigScript returns a standard m-of-n multi-signature script for the
+// passed serialized public keys in the order they are provided.  Every key
+// must be a valid secp256k1 public key.
+func multiSigScript(m int, pubKeys [][]byte) ([]byte, error) {
+	n := len(pubKeys)
+	if n == 0 || n > maxPubKeysPerMultiSig {
+		return nil, fmt.Errorf("number of public keys %d is out of range "+
+			"- must be between 1 and %d", n, maxPubKeysPerMultiSig)
+	}
+	if m < 1 || m > n {
+		return nil, fmt.Errorf("number of required signatures %d is out "+
+			"of range - must be between 1 and %d", m, n)
+	}
+
+	script := make([]byte, 0, 3+n*(1+chainec.Secp256k1.PubKeyBytesLen()))
+	script = append(script, byte(op1-1+m))
+	for i, pubKey := range pubKeys {
+		if _, err := chainec.Secp256k1.ParsePubKey(pubKey); err != nil {
+			return nil, fmt.Errorf("public key %d is invalid: %v", i,
+				err)
+		}
+		script = addScriptData(script, pubKey)
+	}
+	script = append(script, byte(op1-1+n), opCheckMultiSig)
+
+	return script, nil
+}
+
+// NewSortedMultiSigScriptHash returns the pay-to-script-hash address for a
+// standard m-of-n multi-signature redeem script along with the redeem script
+// itself.  The public keys are sorted lexicographically by their serialized
+// bytes before the redeem script is built, so the resulting address does not
+// depend on the order in which the keys are provided.  The passed slice is not
+// modified.
+func NewSortedMultiSigScriptHash(m int, pubKeys [][]byte,
+	net *chaincfg.Params) (*AddressScriptHash, []byte, error) {
+
+	sorted := make([][]byte, len(pubKeys))
+	copy(sorted, pubKeys)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+
+	script, err := multiSigScript(m, sorted)
+	if err != nil {
+		return nil, nil, err
+	}
+	addr, err := NewAddressScriptHash(script, net)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return addr, script, nil
+}
+
+var (
+	// ErrNotMultiSigScript describes an error where a script is not a
+	// standard multi-signature script.
+	ErrNotMultiSigScript = errors.New("script is not a standard " +
+		"multi-signature script")
+
+	// ErrPubKeyNotInScript describes an error where a public key is not
+	// one of the public keys of a multi-signature script.
+	ErrPubKeyNotInScript = errors.New("public key is not in the " +
+		"multi-signature script")
+)
+
+// MultiSigKeyIndex returns the zero-based index of the passed serialized public
+// key among the public keys of the passed multi-signature redeem script, such
+// as one created by NewSortedMultiSigScriptHash.  ErrNotMultiSigScript is
+// returned when the redeem script is not a standard multi-signature script and
+// ErrPubKeyNotInScript is returned when the public key is not one of its keys.
+// Public keys are compared by their serialized bytes, so a compressed key does
+// not match the uncompressed serialization of the same key.
+func MultiSigKeyIndex(redeemScript []byte, pubKey []byte) (int, error) {
+	pops, err := parseScript(redeemScript)
+	if err != nil || !isMultiSig(pops) {
+		return 0, ErrNotMultiSigScript
+	}
+
+	for i, pop := range pops[1 : len(pops)-2] {
+		if bytes.Equal(pop.data, pubKey) {
+			return i, nil
+		}
+	}
+	return 0, ErrPubKeyNotInScript
+}
+
+// errMalformedPush describes an error where a script contains a data push
+// that extends beyond the end of the script.
+var errMalformedPush = errors.New("malformed script push")
+
+// parsedOpcode houses a single opcode from a parsed script along with any data
+// pushed by it.
+type parsedOpcode struct {
+	opcode byte
+	data   []byte
+}
+
+// isSmallInt returns whether or not the opcode is considered a small integer,
+// which is an OP_0, or OP_1 through OP_16.
+func isSmallInt(op byte) bool {
+	return op == opFalse || (op >= op1 && op <= op16)
+}
+
+// asSmallInt returns the small integer represented by the passed opcode.  The
+// opcode must be a small integer as determined by isSmallInt.
+func asSmallInt(op byte) int {
+	if op == opFalse {
+		return 0
+	}
+	return int(op - (op1 - 1))
+}
+
+// isDataPush returns whether or not the opcode pushes data onto the stack,
+// including the small integer and OP_1NEGATE opcodes.
+func isDataPush(op byte) bool {
+	return op <= op16 && op != opReserved
+}
+
+// parseScript splits the passed script into its opcodes along with the data
+// they push.  An error is returned when a push extends beyond the end of the
+// script.
+func parseScript(script []byte) ([]parsedOpcode, error) {
+	var pops []parsedOpcode
+	for i := 0; i < len(script); {
+		op := script[i]
+		i++
+
+		var dataLen int
+		switch {
+		case op >= opData1 && op <= opData75:
+			dataLen = int(op)
+		case op == opPushData1:
+			if len(script)-i < 1 {
+				return nil, errMalformedPush
+			}
+			dataLen = int(script[i])
+			i++
+		case op == opPushData2:
+			if len(script)-i < 2 {
+				return nil, errMalformedPush
+			}
+			dataLen = int(binary.LittleEndian.Uint16(script[i:]))
+			i += 2
+		case op == opPushData4:
+			if len(script)-i < 4 {
+				return nil, errMalformedPush
+			}
+			dataLen = int(binary.LittleEndian.Uint32(script[i:]))
+			i += 4
+		}
+		if dataLen < 0 || len(script)-i < dataLen {
+			return nil, errMalformedPush
+		}
+
+		pop := parsedOpcode{opcode: op}
+		if dataLen > 0 {
+			pop.data = script[i : i+dataLen]
+			i += dataLen
+		}
+		pops = append(pops, pop)
+	}
+	return pops, nil
+}
+
+// scriptClass is an enumeration for the standard script types recognized by
+// this package.
+type scriptClass byte
+
+// Classes of script payment recognized by this package.
+const (
+	nonStandardTy     scriptClass = iota // None of the recognized forms.
+	pubKeyTy                             // Pay pubkey.
+	pubKeyAltTy                          // Alternative signature pubkey.
+	pubKeyHashTy                         // Pay pubkey hash.
+	pubKeyHashAltTy                      // Alternative signature pubkey hash.
+	scriptHashTy                         // Pay to script hash.
+	multiSigTy                           // Multi signature.
+	nullDataTy                           // Empty data-only (provably prunable).
+	stakeSubmissionTy                    // Stake submission.
+	stakeGenTy                           // Stake generation.
+	stakeRevocationTy                    // Stake revocation.
+	stakeSubChangeTy                     // Change for stake submission tx.
+)
+
+// isPubKeyHash returns whether or not the passed opcodes are a standard
+// secp256k1 pay-to-pubkey-hash script.
+func isPubKeyHash(pops []parsedOpcode) bool {
+	return len(pops) == 5 &&
+		pops[0].opcode == opDup &&
+		pops[1].opcode == opHash160 &&
+		pops[2].opcode == opData20 &&
+		pops[3].opcode == opEqualVerify &&
+		pops[4].opcode == opCheckSig
+}
+
+// isPubKeyHashAlt returns whether or not the passed opcodes are a standard
+// pay-to-pubkey-hash script for an alternative signature algorithm.
+func isPubKeyHashAlt(pops []parsedOpcode) bool {
+	return len(pops) == 6 &&
+		pops[0].opcode == opDup &&
+		pops[1].opcode == opHash160 &&
+		pops[2].opcode == opData20 &&
+		pops[3].opcode == opEqualVerify &&
+		isSmallInt(pops[4].opcode) &&
+		pops[5].opcode == opCheckSigAlt
+}
+
+// isScriptHash returns whether or not the passed opcodes are a standard
+// pay-to-script-hash script.
+func isScriptHash(pops []parsedOpcode) bool {
+	return len(pops) == 3 &&
+		pops[0].opcode == opHash160 &&
+		pops[1].opcode == opData20 &&
+		pops[2].opcode == opEqual
+}
+
+// isPubKey returns whether or not the passed opcodes are a standard secp256k1
+// pay-to-pubkey script.
+func isPubKey(pops []parsedOpcode) bool {
+	return len(pops) == 2 &&
+		(len(pops[0].data) == 33 || len(pops[0].data) == 65) &&
+		pops[1].opcode == opCheckSig
+}
+
+// isPubKeyAlt returns whether or not the passed opcodes are a standard
+// pay-to-pubkey script for an alternative signature algorithm.
+func isPubKeyAlt(pops []parsedOpcode) bool {
+	return len(pops) == 3 &&
+		len(pops[0].data) > 0 &&
+		isSmallInt(pops[1].opcode) &&
+		pops[2].opcode == opCheckSigAlt
+}
+
+// isMultiSig returns whether or not the passed opcodes are a standard
+// multi-signature script.
+func isMultiSig(pops []parsedOpcode) bool {
+	// The absolute minimum is 1 pubkey:
+	// OP_0/OP_1-16 <pubkey> OP_1 OP_CHECKMULTISIG
+	l := len(pops)
+	if l < 4 {
+		return false
+	}
+	if !isSmallInt(pops[0].opcode) || !isSmallInt(pops[l-2].opcode) ||
+		pops[l-1].opcode != opCheckMultiSig {
+		return false
+	}
+
+	// Verify the number of pubkeys specified matches the actual number of
+	// pubkeys provided and that the number of required signatures does
+	// not exceed it.
+	numPubKeys := asSmallInt(pops[l-2].opcode)
+	numSigs := asSmallInt(pops[0].opcode)
+	if l-2-1 != numPubKeys || numSigs > numPubKeys {
+		return false
+	}
+	for _, pop := range pops[1 : l-2] {
+		if len(pop.data) != 33 && len(pop.data) != 65 {
+			return false
+		}
+	}
+	return true
+}
+
+// isNullData returns whether or not the passed opcodes are a standard null
+// data script.
+func isNullData(pops []parsedOpcode) bool {
+	// A nulldata script is of the form:
+	// OP_RETURN <optional data>
+	//
+	// Thus, it can either be a single OP_RETURN or an OP_RETURN followed
+	// by a data push up to maxDataCarrierSize bytes.
+	l := len(pops)
+	if l == 1 && pops[0].opcode == opReturn {
+		return true
+	}
+
+	return l == 2 &&
+		pops[0].opcode == opReturn &&
+		isDataPush(pops[1].opcode) &&
+		len(pops[1].data) <= maxDataCarrierSize
+}
+
+// stakeTaggedClass returns the class of a stake output script consisting of
+// the passed stake opcode followed by a pay-to-pubkey-hash or
+// pay-to-script-hash script, or nonStandardTy when the opcodes are not a
+// stake tagged script.
+func stakeTaggedClass(pops []parsedOpcode) scriptClass {
+	if len(pops) < 1 {
+		return nonStandardTy
+	}
+	if rest := pops[1:]; !isPubKeyHash(rest) && !isScriptHash(rest) {
+		return nonStandardTy
+	}
+
+	switch pops[0].opcode {
+	case opSStx:
+		return stakeSubmissionTy
+	case opSSGen:
+		return stakeGenTy
+	case opSSRtx:
+		return stakeRevocationTy
+	case opSStxChange:
+		return stakeSubChangeTy
+	}
+	return nonStandardTy
+}
+
+// typeOfScript returns the class of the passed parsed script.  Only version 0
+// scripts are recognized.
+func typeOfScript(version uint16, pops []parsedOpcode) scriptClass {
+	if version != 0 {
+		return nonStandardTy
+	}
+
+	switch {
+	case isPubKey(pops):
+		return pubKeyTy
+	case isPubKeyAlt(pops):
+		return pubKeyAltTy
+	case isPubKeyHash(pops):
+		return pubKeyHashTy
+	case isPubKeyHashAlt(pops):
+		return pubKeyHashAltTy
+	case isScriptHash(pops):
+		return scriptHashTy
+	case isMultiSig(pops):
+		return multiSigTy
+	case isNullData(pops):
+		return nullDataTy
+	}
+	return stakeTaggedClass(pops)
+}
+
+// altSigDSA returns the digital signature algorithm identified by the small
+// integer signature type used in alternative signature scripts.
+func altSigDSA(op byte) (int, bool) {
+	switch sigType := asSmallInt(op); sigType {
+	case chainec.ECTypeEdwards, chainec.ECTypeSecSchnorr, bliss.BSTypeBliss:
+		return sigType, true
+	}
+	return 0, false
+}
+
+// extractScriptAddress returns the class of the passed script along with the
+// address it pays to.  The address is nil for scripts which do not pay to a
+// single address, such as multi-signature, null data, and non-standard
+// scripts.  An error is returned when the script is of a standard form but
+// contains data which does not produce a valid address.
+func extractScriptAddress(version uint16, script []byte,
+	net *chaincfg.Params) (scriptClass, Address, error) {
+
+	pops, err := parseScript(script)
+	if err != nil {
+		return nonStandardTy, nil, nil
+	}
+
+	class := typeOfScript(version, pops)
+	var addr Address
+	switch class {
+	case pubKeyTy:
+		addr, err = NewAddressSecpPubKey(pops[0].data, net)
+
+	case pubKeyAltTy:
+		dsa, ok := altSigDSA(pops[1].opcode)
+		if !ok {
+			return nonStandardTy, nil, nil
+		}
+		switch dsa {
+		case chainec.ECTypeEdwards:
+			addr, err = NewAddressEdwardsPubKey(pops[0].data, net)
+		case chainec.ECTypeSecSchnorr:
+			addr, err = NewAddressSecSchnorrPubKey(pops[0].data, net)
+		case bliss.BSTypeBliss:
+			addr, err = NewAddressBlissPubKey(pops[0].data, net)
+		}
+
+	case pubKeyHashTy:
+		addr, err = NewAddressPubKeyHash(pops[2].data, net,
+			chainec.ECTypeSecp256k1)
+
+	case pubKeyHashAltTy:
+		dsa, ok := altSigDSA(pops[4].opcode)
+		if !ok {
+			return nonStandardTy, nil, nil
+		}
+		addr, err = NewAddressPubKeyHash(pops[2].data, net, dsa)
+
+	case scriptHashTy:
+		addr, err = NewAddressScriptHashFromHash(pops[1].data, net)
+
+	case stakeSubmissionTy, stakeGenTy, stakeRevocationTy,
+		stakeSubChangeTy:
+
+		// The tagged script is either pay-to-pubkey-hash or
+		// pay-to-script-hash and the hash is pushed by the same
+		// opcode in both cases.
+		if isScriptHash(pops[1:]) {
+			addr, err = NewAddressScriptHashFromHash(pops[2].data, net)
+		} else {
+			addr, err = NewAddressPubKeyHash(pops[3].data, net,
+				chainec.ECTypeSecp256k1)
+		}
+	}
+	if err != nil {
+		return class, nil, err
+	}
+
+	return class, addr, nil
+}
+
+// ScriptToAddressOrReason returns the address paid to by the passed public key
+// script when it is a standard script that pays to a single address.  For all
+// other scripts the address is nil and the reason it does not have one is
+// returned instead, which is one of "bare multisig", "null data", or
+// "non-standard".  An error is only returned when the script is of a standard
+// form but contains data which does not produce a valid address, such as a
+// public key which is not on the curve.
+func ScriptToAddressOrReason(scriptVersion uint16, pkScript []byte,
+	net *chaincfg.Params) (Address, string, error) {
+
+	class, addr, err := extractScriptAddress(scriptVersion, pkScript, net)
+	if err != nil {
+		return nil, "", err
+	}
+
+	switch class {
+	case multiSigTy:
+		return nil, "bare multisig", nil
+	case nullDataTy:
+		return nil, "null data", nil
+	case nonStandardTy:
+		return nil, "non-standard", nil
+	}
+	return addr, "", nil
+}
+
+// SumOutputsToAddress returns the total value and number of the passed outputs
+// which pay to the passed address.  Addresses are compared with AddressesEqual,
+// so a pay-to-pubkey output does not pay to the pay-to-pubkey-hash address of
+// its public key.  Outputs with scripts that do not pay to a single address,
+// such as multi-signature, null data, and non-standard scripts, are skipped.
+// ErrWrongNetwork is returned when the address is not for the passed network.
+func SumOutputsToAddress(outs []*wire.TxOut, addr Address,
+	net *chaincfg.Params) (Amount, int, error) {
+
+	if addr == nil {
+		return 0, 0, errors.New("no address")
+	}
+	if !addr.IsForNet(net) {
+		return 0, 0, ErrWrongNetwork
+	}
+
+	var total Amount
+	var count int
+	for _, txOut := range outs {
+		_, outAddr, err := extractScriptAddress(txOut.Version,
+			txOut.PkScript, net)
+		if err != nil || outAddr == nil {
+			continue
+		}
+		if AddressesEqual(outAddr, addr) {
+			total += Amount(txOut.Value)
+			count++
+		}
+	}
+	return total, count, nil
+}
+
+// AddressMatchesScript returns whether or not the passed public key script pays
+// to the passed address.  Like SumOutputsToAddress, addresses are compared
+// with AddressesEqual, so a pay-to-pubkey script does not match the
+// pay-to-pubkey-hash address of its public key.  Scripts which do not pay to a
+// single address never match.  ErrWrongNetwork is returned when the address
+// is not for the passed network, and an error is returned when the script is of
+// a standard form but contains data which does not produce a valid address.
+func AddressMatchesScript(addr Address, scriptVersion uint16, pkScript []byte,
+	net *chaincfg.Params) (bool, error) {
+
+	if addr == nil {
+		return false, errors.New("no address")
+	}
+	if !addr.IsForNet(net) {
+		return false, ErrWrongNetwork
+	}
+
+	_, scriptAddr, err := extractScriptAddress(scriptVersion, pkScript, net)
+	if err != nil {
+		return false, err
+	}
+	if scriptAddr == nil {
+		return false, nil
+	}
+	return AddressesEqual(scriptAddr, addr), nil
+}
+
+// SpendsFrom returns whether or not any input of the transaction spends an
+// output which pays to the passed address along with the indices of every such
+// input in order.  The public key scripts of the outputs spent by the inputs
+// are looked up in prevScripts by the previous outpoint of each input and are
+// assumed to be of the default script version.  Inputs with no entry in
+// prevScripts, and scripts which do not pay to a single address, are treated
+// as not matching, as are all inputs when the address is not for the passed
+// network.
+func (t *Tx) SpendsFrom(addr Address, prevScripts map[wire.OutPoint][]byte,
+	net *chaincfg.Params) (bool, []int) {
+
+	var matches []int
+	for i, txIn := range t.msgTx.TxIn {
+		pkScript, ok := prevScripts[txIn.PreviousOutPoint]
+		if !ok {
+			continue
+		}
+		match, err := AddressMatchesScript(addr,
+			wire.DefaultPkScriptVersion, pkScript, net)
+		if err != nil || !match {
+			continue
+		}
+		matches = append(matches, i)
+	}
+	return len(matches) > 0, matches
+}
+
+// maxMultiSigOps is the number of signature operations counted for a
+// multi-signature check when the number of public keys is not known.  It is
+// the maximum number of public keys allowed by consensus.
+const maxMultiSigOps = 20
+
+// countSigOps returns the number of signature operations in the passed parsed
+// script.  When precise is set, multi-signature checks preceded by a small
+// integer count as that many public keys rather than maxMultiSigOps, which is
+// how redeem scripts are counted.
+func countSigOps(pops []parsedOpcode, precise bool) int {
+	numSigOps := 0
+	for i, pop := range pops {
+		switch pop.opcode {
+		case opCheckSig, opCheckSigVerify, opCheckSigAlt,
+			opCheckSigAltVerify:
+
+			numSigOps++
+
+		case opCheckMultiSig, opCheckMultiSigVerify:
+			if precise && i > 0 && pops[i-1].opcode >= op1 &&
+				pops[i-1].opcode <= op16 {
+
+				numSigOps += asSmallInt(pops[i-1].opcode)
+			} else {
+				numSigOps += maxMultiSigOps
+			}
+		}
+	}
+	return numSigOps
+}
+
+// isAnyScriptHash returns whether or not the passed opcodes are a standard
+// pay-to-script-hash script, either alone or tagged with a stake opcode.
+func isAnyScriptHash(pops []parsedOpcode) bool {
+	return isScriptHash(pops) ||
+		(stakeTaggedClass(pops) != nonStandardTy && isScriptHash(pops[1:]))
+}
+
+// SigOpCount returns the number of signature operations in the signature
+// scripts of the inputs and the public key scripts of the outputs of the
+// transaction as counted for block validation.  The inputs of coinbases and
+// the stakebase input of votes are not counted.
+//
+// The public key scripts of the outputs spent by the inputs are looked up in
+// prevScripts by the previous outpoint of each input.  When an input spends a
+// pay-to-script-hash output, including a stake tagged one, the signature
+// operations of the redeem script pushed last by its signature script are
+// also counted, using the number of public keys of multi-signature checks.
+// Inputs with no entry in prevScripts are counted without a redeem script.
+//
+// An error is returned when a script is malformed or when the signature
+// script of an input spending a pay-to-script-hash output does not only push
+// data.
+func (t *Tx) SigOpCount(prevScripts map[wire.OutPoint][]byte) (int, error) {
+	msgTx := t.msgTx
+	txIns := msgTx.TxIn
+	switch {
+	case isCoinBase(msgTx):
+		txIns = nil
+	case isVote(msgTx):
+		txIns = txIns[1:]
+	}
+
+	numSigOps := 0
+	for _, txIn := range txIns {
+		pops, err := parseScript(txIn.SignatureScript)
+		if err != nil {
+			return 0, fmt.Errorf("malformed signature script spending "+
+				"%v: %v", txIn.PreviousOutPoint, err)
+		}
+		numSigOps += countSigOps(pops, false)
+
+		pkScript, ok := prevScripts[txIn.PreviousOutPoint]
+		if !ok {
+			continue
+		}
+		pkPops, err := parseScript(pkScript)
+		if err != nil {
+			return 0, fmt.Errorf("malformed script of output %v: %v",
+				txIn.PreviousOutPoint, err)
+		}
+		if !isAnyScriptHash(pkPops) {
+			continue
+		}
+
+		// The redeem script is the final data push of the signature
+		// script, which must only push data.
+		if len(pops) == 0 {
+			continue
+		}
+		for _, pop := range pops {
+			if pop.opcode > op16 {
+				return 0, fmt.Errorf("signature script spending "+
+					"pay-to-script-hash output %v is not push "+
+					"only", txIn.PreviousOutPoint)
+			}
+		}
+		redeemPops, err := parseScript(pops[len(pops)-1].data)
+		if err != nil {
+			return 0, fmt.Errorf("malformed redeem script spending "+
+				"%v: %v", txIn.PreviousOutPoint, err)
+		}
+		numSigOps += countSigOps(redeemPops, true)
+	}
+
+	for i, txOut := range msgTx.TxOut {
+		pops, err := parseScript(txOut.PkScript)
+		if err != nil {
+			return 0, fmt.Errorf("malformed script of output %d: %v",
+				i, err)
+		}
+		numSigOps += countSigOps(pops, false)
+	}
+	return numSigOps, nil
+}
+
+// AllOutputsStandard returns whether or not every output of the transaction
+// pays to a recognized standard script type along with the index of the first
+// output which does not, or -1 when they all do.  Outputs of a standard form
+// which contain data that does not produce a valid address for the passed
+// network, such as a public key that is not on the curve, are not standard.
+func (t *Tx) AllOutputsStandard(net *chaincfg.Params) (bool, int) {
+	for i, txOut := range t.msgTx.TxOut {
+		class, _, err := extractScriptAddress(txOut.Version,
+			txOut.PkScript, net)
+		if err != nil || class == nonStandardTy {
+			return false, i
+		}
+	}
+	return true, -1
+}
+
+// ReusedOutputAddresses returns the addresses paid to by more than one output of
+// the transaction, in the order they are first paid to.  Each reused address is
+// only returned once.  Outputs with scripts that do not pay to a single
+// address, such as multi-signature, null data, and non-standard scripts, are
+// skipped.  An error is returned when an output script is of a standard form
+// but contains data which does not produce a valid address.
+func (t *Tx) ReusedOutputAddresses(net *chaincfg.Params) ([]Address, error) {
+	counts := make(map[string]int)
+	var addrs []Address
+	for _, txOut := range t.msgTx.TxOut {
+		_, addr, err := extractScriptAddress(txOut.Version,
+			txOut.PkScript, net)
+		if err != nil {
+			return nil, err
+		}
+		if addr == nil {
+			continue
+		}
+
+		encoded := addr.EncodeAddress()
+		counts[encoded]++
+		if counts[encoded] == 2 {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// roundChangeUnit is the granularity, in atoms, at which an output value is
+// considered a round number by LikelyChangeIndex.
+const roundChangeUnit = 1e6
+
+// LikelyChangeIndex returns the index of the output of the transaction which is
+// most likely to be change returned to the sender, and whether or not a likely
+// change output was found.
+//
+// The first output paying to an address in knownChangeAddrs is returned when
+// there is one.  Otherwise, the following heuristic is applied to transactions
+// with exactly two outputs, the typical form of a payment with change:
+//
+//   - Payments are usually for round amounts while change is whatever is left
+//     over, so when exactly one output value is a multiple of 0.01 coin, the
+//     other output is the change.
+//   - Otherwise, the output with the smaller value is the change.  No change
+//     output is reported when both values are equal.
+//
+// The heuristic is only a guess and is easily defeated by wallets which
+// deliberately avoid these patterns.  A nil knownChangeAddrs skips the known
+// address check.
+func (t *Tx) LikelyChangeIndex(knownChangeAddrs *AddressSet, net *chaincfg.Params) (int, bool) {
+	txOuts := t.msgTx.TxOut
+	if knownChangeAddrs != nil {
+		for i, txOut := range txOuts {
+			_, addr, err := extractScriptAddress(txOut.Version,
+				txOut.PkScript, net)
+			if err != nil || addr == nil {
+				continue
+			}
+			if knownChangeAddrs.Contains(addr) {
+				return i, true
+			}
+		}
+	}
+
+	if len(txOuts) != 2 {
+		return -1, false
+	}
+	value0, value1 := txOuts[0].Value, txOuts[1].Value
+	round0 := value0%roundChangeUnit == 0
+	round1 := value1%roundChangeUnit == 0
+	switch {
+	case round0 && !round1:
+		return 1, true
+	case round1 && !round0:
+		return 0, true
+	case value0 < value1:
+		return 0, true
+	case value1 < value0:
+		return 1, true
+	}
+	return -1, false
+}
+
+// stakeAddressHash returns the hash paid to by the passed address along with
+// whether or not it is a script hash.  Only secp256k1 pay-to-pubkey-hash and
+// pay-to-script-hash addresses are supported by the stake scripts.
+func stakeAddressHash(addr Address) ([]byte, bool, error) {
+	switch addr := addr.(type) {
+	case *AddressPubKeyHash:
+		if addr == nil {
+			break
+		}
+		if addr.DSA(addr.Net()) != chainec.ECTypeSecp256k1 {
+			return nil, false, fmt.Errorf("unsupported signature "+
+				"algorithm for stake script address %v", addr)
+		}
+		return addr.hash[:], false, nil
+
+	case *AddressScriptHash:
+		if addr == nil {
+			break
+		}
+		return addr.hash[:], true, nil
+	}
+
+	return nil, false, fmt.Errorf("unsupported stake script address "+
+		"type %T", addr)
+}
+
+// payToStakeTaggedScript returns a pay-to-pubkey-hash or pay-to-script-hash
+// script for the passed address prefixed by the passed stake opcode.
+func payToStakeTaggedScript(stakeOpcode byte, addr Address) ([]byte, error) {
+	hash, isScriptHash, err := stakeAddressHash(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	if isScriptHash {
+		script := make([]byte, 0, 24)
+		script = append(script, stakeOpcode, opHash160, opData20)
+		script = append(script, hash...)
+		return append(script, opEqual), nil
+	}
+
+	script := make([]byte, 0, 26)
+	script = append(script, stakeOpcode, opDup, opHash160, opData20)
+	script = append(script, hash...)
+	return append(script, opEqualVerify, opCheckSig), nil
+}
+
+// payToAddrScript returns the standard public key script which pays to the
+// passed address.  It mirrors PayToAddrScript in the txscript package, which
+// can not be imported here.
+func payToAddrScript(addr Address) ([]byte, error) {
+	// sigTypeOp returns the small integer opcode which identifies the
+	// passed alternative signature algorithm in OP_CHECKSIGALT scripts.
+	sigTypeOp := func(dsa int) byte {
+		return byte(op1 - 1 + dsa)
+	}
+
+	switch addr := addr.(type) {
+	case *AddressPubKeyHash:
+		if addr == nil {
+			break
+		}
+		script := make([]byte, 0, 26)
+		script = append(script, opDup, opHash160, opData20)
+		script = append(script, addr.hash[:]...)
+		script = append(script, opEqualVerify)
+		dsa := addr.DSA(addr.Net())
+		if dsa == chainec.ECTypeSecp256k1 {
+			return append(script, opCheckSig), nil
+		}
+		return append(script, sigTypeOp(dsa), opCheckSigAlt), nil
+
+	case *AddressScriptHash:
+		if addr == nil {
+			break
+		}
+		script := make([]byte, 0, 23)
+		script = append(script, opHash160, opData20)
+		script = append(script, addr.hash[:]...)
+		return append(script, opEqual), nil
+
+	case *AddressSecpPubKey:
+		if addr == nil {
+			break
+		}
+		script := addScriptData(nil, addr.ScriptAddress())
+		return append(script, opCheckSig), nil
+
+	case *AddressEdwardsPubKey:
+		if addr == nil {
+			break
+		}
+		script := addScriptData(nil, addr.ScriptAddress())
+		return append(script, sigTypeOp(chainec.ECTypeEdwards),
+			opCheckSigAlt), nil
+
+	case *AddressSecSchnorrPubKey:
+		if addr == nil {
+			break
+		}
+		script := addScriptData(nil, addr.ScriptAddress())
+		return append(script, sigTypeOp(chainec.ECTypeSecSchnorr),
+			opCheckSigAlt), nil
+
+	case *AddressBlissPubKey:
+		if addr == nil {
+			break
+		}
+		script := addScriptData(nil, addr.ScriptAddress())
+		return append(script, sigTypeOp(bliss.BSTypeBliss),
+			opCheckSigAlt), nil
+	}
+
+	return nil, fmt.Errorf("unsupported address type %T", addr)
+}
+
+// PkScriptHex returns the hex encoding of the standard public key script which
+// pays to the passed address.  An error is returned for nil addresses and
+// addresses of types not defined by this package.
+func PkScriptHex(addr Address) (string, error) {
+	script, err := payToAddrScript(addr)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(script), nil
+}
+
+// PayToSStx returns the ticket submission output script which pays to the
+// passed address.  The address must be a secp256k1 pay-to-pubkey-hash or a
+// pay-to-script-hash address.
+func PayToSStx(addr Address) ([]byte, error) {
+	return payToStakeTaggedScript(opSStx, addr)
+}
@@ -0,0 +1,866 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcutil"
+	"github.com/HcashOrg/hcutil/base58"
+)
+
+// hexToBytes converts the passed hex string into bytes and will panic if there
+// is an error.  This is only provided for the hard-coded constants so errors in
+// the source code can be detected. It will only (and must only) be called with
+// hard-coded values.
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// TestNewSortedMultiSigScriptHash ensures the sorted multi-signature script
+// hash address does not depend on the order of the provided public keys.
+func TestNewSortedMultiSigScriptHash(t *testing.T) {
+	pubKeyA := hexToBytes("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce2" +
+		"8d959f2815b16f81798")
+	pubKeyB := hexToBytes("02192d74d0cb94344c9569c2e77901573d8d7903c3ebec3" +
+		"a957724895dca52c6b4")
+	pubKeyC := hexToBytes("03b0bd634234abbb1ba1e986e884185c61cf43e001f9137" +
+		"f23c2c409273eb16e65")
+
+	// The expected redeem script has the keys in lexicographic order.
+	var wantScript []byte
+	wantScript = append(wantScript, 0x52) // OP_2
+	for _, pubKey := range [][]byte{pubKeyB, pubKeyA, pubKeyC} {
+		wantScript = append(wantScript, 0x21) // OP_DATA_33
+		wantScript = append(wantScript, pubKey...)
+	}
+	wantScript = append(wantScript, 0x53, 0xae) // OP_3 OP_CHECKMULTISIG
+	wantAddr, err := hcutil.NewAddressScriptHash(wantScript,
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHash: unexpected error: %v", err)
+	}
+
+	orders := [][][]byte{
+		{pubKeyA, pubKeyB, pubKeyC},
+		{pubKeyC, pubKeyB, pubKeyA},
+		{pubKeyB, pubKeyC, pubKeyA},
+	}
+	for i, pubKeys := range orders {
+		first := pubKeys[0]
+		addr, script, err := hcutil.NewSortedMultiSigScriptHash(2,
+			pubKeys, &chaincfg.MainNetParams)
+		if err != nil {
+			t.Errorf("order #%d: unexpected error: %v", i, err)
+			continue
+		}
+		if !bytes.Equal(script, wantScript) {
+			t.Errorf("order #%d: mismatched script - got %x, want %x",
+				i, script, wantScript)
+		}
+		if addr.EncodeAddress() != wantAddr.EncodeAddress() {
+			t.Errorf("order #%d: mismatched address - got %v, want %v",
+				i, addr, wantAddr)
+		}
+		if !bytes.Equal(pubKeys[0], first) {
+			t.Errorf("order #%d: input keys were reordered", i)
+		}
+	}
+
+	// Ensure invalid signature counts and keys are rejected.
+	pubKeys := [][]byte{pubKeyA, pubKeyB}
+	for _, m := range []int{0, 3} {
+		_, _, err := hcutil.NewSortedMultiSigScriptHash(m, pubKeys,
+			&chaincfg.MainNetParams)
+		if err == nil {
+			t.Errorf("m=%d: expected error for out of range signature "+
+				"count", m)
+		}
+	}
+	badKeys := [][]byte{pubKeyA, pubKeyB[1:]}
+	_, _, err = hcutil.NewSortedMultiSigScriptHash(1, badKeys,
+		&chaincfg.MainNetParams)
+	if err == nil {
+		t.Error("expected error for invalid public key")
+	}
+}
+
+// TestMultiSigKeyIndex ensures the index of a public key in a multi-signature
+// redeem script is found and missing keys and other scripts are rejected.
+func TestMultiSigKeyIndex(t *testing.T) {
+	pubKeyA := hexToBytes("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce2" +
+		"8d959f2815b16f81798")
+	pubKeyB := hexToBytes("02192d74d0cb94344c9569c2e77901573d8d7903c3ebec3" +
+		"a957724895dca52c6b4")
+	pubKeyC := hexToBytes("03b0bd634234abbb1ba1e986e884185c61cf43e001f9137" +
+		"f23c2c409273eb16e65")
+	_, script, err := hcutil.NewSortedMultiSigScriptHash(2,
+		[][]byte{pubKeyA, pubKeyB, pubKeyC}, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewSortedMultiSigScriptHash: unexpected error: %v", err)
+	}
+
+	// The keys are sorted in the script as B, A, C.
+	tests := []struct {
+		name   string
+		pubKey []byte
+		want   int
+	}{
+		{name: "key A", pubKey: pubKeyA, want: 1},
+		{name: "key B", pubKey: pubKeyB, want: 0},
+		{name: "key C", pubKey: pubKeyC, want: 2},
+	}
+	for _, test := range tests {
+		got, err := hcutil.MultiSigKeyIndex(script, test.pubKey)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: mismatched index - got %d, want %d",
+				test.name, got, test.want)
+		}
+	}
+
+	// Ensure a key which is not in the script is rejected.
+	missing := hexToBytes("03" + strings.Repeat("01", 32))
+	_, err = hcutil.MultiSigKeyIndex(script, missing)
+	if err != hcutil.ErrPubKeyNotInScript {
+		t.Errorf("missing key: mismatched error - got %v, want %v", err,
+			hcutil.ErrPubKeyNotInScript)
+	}
+
+	// Ensure scripts which are not multi-signature scripts are rejected.
+	for _, test := range []struct {
+		name   string
+		script []byte
+	}{
+		{name: "p2pkh", script: hexToBytes("76a914" +
+			"1234567890abcdef1234567890abcdef1234567888ac")},
+		{name: "truncated", script: script[:len(script)-2]},
+		{name: "empty", script: nil},
+	} {
+		_, err := hcutil.MultiSigKeyIndex(test.script, pubKeyA)
+		if err != hcutil.ErrNotMultiSigScript {
+			t.Errorf("%s: mismatched error - got %v, want %v",
+				test.name, err, hcutil.ErrNotMultiSigScript)
+		}
+	}
+}
+
+// TestSignatureScriptSize ensures the expected sizes are returned for the
+// signature scripts which redeem pay-to-pubkey-hash outputs.
+func TestSignatureScriptSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		algo       int
+		compressed bool
+		want       int
+	}{
+		{"secp256k1 compressed", chainec.ECTypeSecp256k1, true, 108},
+		{"secp256k1 uncompressed", chainec.ECTypeSecp256k1, false, 140},
+		{"edwards", chainec.ECTypeEdwards, true, 99},
+		{"schnorr", chainec.ECTypeSecSchnorr, true, 100},
+		{"unknown", -1, true, 0},
+	}
+	for _, test := range tests {
+		got := hcutil.SignatureScriptSize(test.algo, test.compressed)
+		if got != test.want {
+			t.Errorf("%s: mismatched size - got %d, want %d", test.name,
+				got, test.want)
+		}
+	}
+}
+
+// TestScriptToAddressOrReason ensures addresses are extracted from standard
+// scripts and the expected reasons are given for scripts without a single
+// address.
+func TestScriptToAddressOrReason(t *testing.T) {
+	pubKey := hexToBytes("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d" +
+		"959f2815b16f81798")
+	hash := hexToBytes("2789d58cfa0957d206f025c2af056fc8a77cebb0")
+	net := &chaincfg.MainNetParams
+
+	p2pkh := append(append([]byte{0x76, 0xa9, 0x14}, hash...), 0x88, 0xac)
+	p2sh := append(append([]byte{0xa9, 0x14}, hash...), 0x87)
+	p2pkhSchnorr := append(append([]byte{0x76, 0xa9, 0x14}, hash...), 0x88,
+		0x52, 0xbe)
+	p2pk := append(append([]byte{0x21}, pubKey...), 0xac)
+	badP2PK := append(append([]byte{0x21, 0x02}, hash...), hash[:12]...)
+	badP2PK = append(badP2PK, 0xac)
+	multiSig := append(append([]byte{0x51, 0x21}, pubKey...), 0x51, 0xae)
+	sstx := append([]byte{0xba}, p2pkh...)
+
+	tests := []struct {
+		name       string
+		version    uint16
+		script     []byte
+		wantAddr   string
+		wantReason string
+		wantErr    bool
+	}{
+		{
+			name:     "p2pkh",
+			script:   p2pkh,
+			wantAddr: "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+		},
+		{
+			name:     "p2sh",
+			script:   p2sh,
+			wantAddr: base58.CheckEncode(hash, net.ScriptHashAddrID),
+		},
+		{
+			name:     "schnorr p2pkh",
+			script:   p2pkhSchnorr,
+			wantAddr: base58.CheckEncode(hash, net.PKHSchnorrAddrID),
+		},
+		{
+			name:     "p2pk",
+			script:   p2pk,
+			wantAddr: base58.CheckEncode(hcutil.Hash160(pubKey), net.PubKeyHashAddrID),
+		},
+		{
+			name:     "stake submission",
+			script:   sstx,
+			wantAddr: "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu",
+		},
+		{
+			name:       "bare multisig",
+			script:     multiSig,
+			wantReason: "bare multisig",
+		},
+		{
+			name:       "null data",
+			script:     []byte{0x6a, 0x04, 0x01, 0x02, 0x03, 0x04},
+			wantReason: "null data",
+		},
+		{
+			name:       "non-standard",
+			script:     []byte{0x51},
+			wantReason: "non-standard",
+		},
+		{
+			name:       "malformed push",
+			script:     []byte{0x4c, 0x10, 0x01},
+			wantReason: "non-standard",
+		},
+		{
+			name:       "unknown script version",
+			version:    1,
+			script:     p2pkh,
+			wantReason: "non-standard",
+		},
+		{
+			name:    "invalid pubkey",
+			script:  badP2PK,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		addr, reason, err := hcutil.ScriptToAddressOrReason(test.version,
+			test.script, net)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if reason != test.wantReason {
+			t.Errorf("%s: mismatched reason - got %q, want %q",
+				test.name, reason, test.wantReason)
+		}
+		if test.wantAddr == "" {
+			if addr != nil {
+				t.Errorf("%s: unexpected address %v", test.name,
+					addr)
+			}
+			continue
+		}
+		if addr == nil {
+			t.Errorf("%s: missing address", test.name)
+			continue
+		}
+		if got := addr.EncodeAddress(); got != test.wantAddr {
+			t.Errorf("%s: mismatched address - got %s, want %s",
+				test.name, got, test.wantAddr)
+		}
+	}
+}
+
+// TestPayToSStx ensures ticket submission output scripts are created as
+// expected for supported addresses and unsupported addresses are rejected.
+func TestPayToSStx(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := hexToBytes("1234567890abcdef1234567890abcdef12345678")
+	p2pkh, err := hcutil.NewAddressPubKeyHash(hash, net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2pkhEd, err := hcutil.NewAddressPubKeyHash(hash, net,
+		chainec.ECTypeEdwards)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2sh, err := hcutil.NewAddressScriptHashFromHash(hash, net)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHashFromHash: unexpected error: %v", err)
+	}
+	p2pk, err := hcutil.NewAddressSecpPubKey(hexToBytes("02192d74d0cb9"+
+		"4344c9569c2e77901573d8d7903c3ebec3a957724895dca52c6b4"), net)
+	if err != nil {
+		t.Fatalf("NewAddressSecpPubKey: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		addr    hcutil.Address
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "p2pkh",
+			addr: p2pkh,
+			want: "ba76a9141234567890abcdef1234567890abcdef1234567" +
+				"888ac",
+		},
+		{
+			name: "p2sh",
+			addr: p2sh,
+			want: "baa9141234567890abcdef1234567890abcdef1234567887",
+		},
+		{
+			name:    "edwards p2pkh",
+			addr:    p2pkhEd,
+			wantErr: true,
+		},
+		{
+			name:    "p2pk",
+			addr:    p2pk,
+			wantErr: true,
+		},
+		{
+			name:    "nil address",
+			addr:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		script, err := hcutil.PayToSStx(test.addr)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if !bytes.Equal(script, hexToBytes(test.want)) {
+			t.Errorf("%s: mismatched script - got %x, want %s",
+				test.name, script, test.want)
+		}
+	}
+}
+
+// pubKeyHex is a compressed secp256k1 public key used to test that
+// pay-to-pubkey scripts are not confused with the pay-to-pubkey-hash address
+// of their public key.
+const pubKeyHex = "0264c44653d6567eff5753c5d24a682ddc2b2cadfe1b0c6433b16374da" +
+	"ce6778f0"
+
+// pubKeyAddrPair returns the pay-to-pubkey address of pubKeyHex along with
+// the pay-to-pubkey-hash address of its hash.
+func pubKeyAddrPair(t *testing.T, net *chaincfg.Params) (hcutil.Address,
+	hcutil.Address) {
+
+	pubKey := hexToBytes(pubKeyHex)
+	pkAddr, err := hcutil.NewAddressSecpPubKey(pubKey, net)
+	if err != nil {
+		t.Fatalf("NewAddressSecpPubKey: unexpected error: %v", err)
+	}
+	pkhAddr, err := hcutil.NewAddressPubKeyHash(hcutil.Hash160(pubKey), net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	return pkAddr, pkhAddr
+}
+
+// TestSumOutputsToAddress ensures only outputs paying to the requested address
+// are counted and non-standard scripts are skipped.
+func TestSumOutputsToAddress(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := "1234567890abcdef1234567890abcdef12345678"
+	addr, err := hcutil.NewAddressPubKeyHash(hexToBytes(hash), net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	outs := []*wire.TxOut{
+		// Pay-to-pubkey-hash to the address.
+		wire.NewTxOut(1000, hexToBytes("76a914"+hash+"88ac")),
+		// Pay-to-script-hash with the same hash.
+		wire.NewTxOut(2000, hexToBytes("a914"+hash+"87")),
+		// Null data.
+		wire.NewTxOut(0, hexToBytes("6a0401020304")),
+		// Non-standard.
+		wire.NewTxOut(4000, hexToBytes("76a914")),
+		// Pay-to-pubkey-hash to a different address.
+		wire.NewTxOut(8000, hexToBytes("76a914"+
+			"0000000000000000000000000000000000000000"+"88ac")),
+		// Stake generation payment to the address.
+		wire.NewTxOut(16000, hexToBytes("bb76a914"+hash+"88ac")),
+		// Pay-to-pubkey-hash to the address with an unknown script
+		// version.
+		{Value: 32000, Version: 1, PkScript: hexToBytes("76a914" + hash +
+			"88ac")},
+	}
+
+	total, count, err := hcutil.SumOutputsToAddress(outs, addr, net)
+	if err != nil {
+		t.Fatalf("SumOutputsToAddress: unexpected error: %v", err)
+	}
+	if total != 17000 || count != 2 {
+		t.Errorf("SumOutputsToAddress: mismatched result - got %d "+
+			"atoms in %d outputs, want 17000 atoms in 2 outputs",
+			int64(total), count)
+	}
+
+	// Ensure a pay-to-pubkey output only pays to the pay-to-pubkey address
+	// and not to the pay-to-pubkey-hash address of its public key.
+	pkAddr, pkhAddr := pubKeyAddrPair(t, net)
+	pkOuts := []*wire.TxOut{wire.NewTxOut(1000, hexToBytes("21"+pubKeyHex+
+		"ac"))}
+	total, count, err = hcutil.SumOutputsToAddress(pkOuts, pkAddr, net)
+	if err != nil || total != 1000 || count != 1 {
+		t.Errorf("SumOutputsToAddress: mismatched p2pk result - got %d "+
+			"atoms in %d outputs (err %v), want 1000 atoms in 1 output",
+			int64(total), count, err)
+	}
+	total, count, err = hcutil.SumOutputsToAddress(pkOuts, pkhAddr, net)
+	if err != nil || total != 0 || count != 0 {
+		t.Errorf("SumOutputsToAddress: mismatched p2pkh result - got %d "+
+			"atoms in %d outputs (err %v), want 0 atoms in 0 outputs",
+			int64(total), count, err)
+	}
+
+	// Ensure an address for another network is rejected.
+	_, _, err = hcutil.SumOutputsToAddress(outs, addr,
+		&chaincfg.TestNet2Params)
+	if err != hcutil.ErrWrongNetwork {
+		t.Errorf("SumOutputsToAddress: mismatched error - got %v, want "+
+			"%v", err, hcutil.ErrWrongNetwork)
+	}
+}
+
+// TestAddressMatchesScript ensures addresses are only reported as matching
+// scripts which pay to them.
+func TestAddressMatchesScript(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := "1234567890abcdef1234567890abcdef12345678"
+	addr, err := hcutil.NewAddressPubKeyHash(hexToBytes(hash), net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		version uint16
+		script  string
+		want    bool
+	}{
+		{
+			name:   "matching p2pkh",
+			script: "76a914" + hash + "88ac",
+			want:   true,
+		},
+		{
+			name: "p2pkh to a different address",
+			script: "76a914" + "0000000000000000000000000000000000000000" +
+				"88ac",
+			want: false,
+		},
+		{
+			name:   "p2sh with the same hash",
+			script: "a914" + hash + "87",
+			want:   false,
+		},
+		{
+			name:   "null data",
+			script: "6a0401020304",
+			want:   false,
+		},
+		{
+			name:    "unknown script version",
+			version: 1,
+			script:  "76a914" + hash + "88ac",
+			want:    false,
+		},
+	}
+	for _, test := range tests {
+		got, err := hcutil.AddressMatchesScript(addr, test.version,
+			hexToBytes(test.script), net)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: mismatched result - got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+
+	// Ensure a pay-to-pubkey script only matches the pay-to-pubkey address
+	// and not the pay-to-pubkey-hash address of its public key.
+	pkAddr, pkhAddr := pubKeyAddrPair(t, net)
+	pkScript := hexToBytes("21" + pubKeyHex + "ac")
+	got, err := hcutil.AddressMatchesScript(pkAddr, 0, pkScript, net)
+	if err != nil || !got {
+		t.Errorf("AddressMatchesScript: p2pk address did not match p2pk "+
+			"script (err %v)", err)
+	}
+	got, err = hcutil.AddressMatchesScript(pkhAddr, 0, pkScript, net)
+	if err != nil || got {
+		t.Errorf("AddressMatchesScript: p2pkh address matched p2pk "+
+			"script (err %v)", err)
+	}
+
+	// Ensure an address for another network is rejected.
+	_, err = hcutil.AddressMatchesScript(addr, 0,
+		hexToBytes("76a914"+hash+"88ac"), &chaincfg.TestNet2Params)
+	if err != hcutil.ErrWrongNetwork {
+		t.Errorf("AddressMatchesScript: mismatched error - got %v, want "+
+			"%v", err, hcutil.ErrWrongNetwork)
+	}
+}
+
+// TestTxSpendsFrom ensures inputs spending outputs which pay to an address are
+// detected from the provided previous output scripts.
+func TestTxSpendsFrom(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := "1234567890abcdef1234567890abcdef12345678"
+	otherHash := "0000000000000000000000000000000000000001"
+	addr, err := hcutil.NewAddressPubKeyHash(hexToBytes(hash), net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	// Spend outputs paying to the address, another address, a script hash
+	// with the same hash, and an unknown output.
+	prevOuts := []wire.OutPoint{
+		{Hash: chainhash.Hash{0x01}, Index: 0},
+		{Hash: chainhash.Hash{0x02}, Index: 1},
+		{Hash: chainhash.Hash{0x03}, Index: 0},
+		{Hash: chainhash.Hash{0x01}, Index: 2},
+		{Hash: chainhash.Hash{0x04}, Index: 0},
+	}
+	prevScripts := map[wire.OutPoint][]byte{
+		prevOuts[0]: hexToBytes("76a914" + hash + "88ac"),
+		prevOuts[1]: hexToBytes("76a914" + otherHash + "88ac"),
+		prevOuts[2]: hexToBytes("a914" + hash + "87"),
+		prevOuts[3]: hexToBytes("76a914" + hash + "88ac"),
+	}
+	tx := wire.NewMsgTx()
+	for i := range prevOuts {
+		tx.AddTxIn(wire.NewTxIn(&prevOuts[i], 0, nil))
+	}
+
+	spends, indices := hcutil.NewTx(tx).SpendsFrom(addr, prevScripts, net)
+	if !spends || !reflect.DeepEqual(indices, []int{0, 3}) {
+		t.Errorf("SpendsFrom: mismatched result - got (%v, %v), want "+
+			"(true, [0 3])", spends, indices)
+	}
+
+	// No inputs match an address which is not spent from or which is for
+	// another network.
+	otherAddr, err := hcutil.NewAddressPubKeyHash(hexToBytes(otherHash),
+		&chaincfg.TestNet2Params, chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	delete(prevScripts, prevOuts[0])
+	delete(prevScripts, prevOuts[3])
+	for _, a := range []hcutil.Address{addr, otherAddr} {
+		spends, indices := hcutil.NewTx(tx).SpendsFrom(a, prevScripts, net)
+		if spends || len(indices) != 0 {
+			t.Errorf("SpendsFrom: unexpected match for %v - got (%v, "+
+				"%v)", a, spends, indices)
+		}
+	}
+}
+
+// TestTxSigOpCount ensures the signature operations of transactions are
+// counted as expected, including those of pay-to-script-hash redeem scripts.
+func TestTxSigOpCount(t *testing.T) {
+	pubKeys := [][]byte{
+		hexToBytes("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f" +
+			"2815b16f81798"),
+		hexToBytes("02192d74d0cb94344c9569c2e77901573d8d7903c3ebec3a95772" +
+			"4895dca52c6b4"),
+		hexToBytes("03b0bd634234abbb1ba1e986e884185c61cf43e001f9137f23c2c" +
+			"409273eb16e65"),
+	}
+	p2sh, redeemScript, err := hcutil.NewSortedMultiSigScriptHash(2,
+		pubKeys, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewSortedMultiSigScriptHash: unexpected error: %v", err)
+	}
+	p2shScript := append([]byte{0xa9, 0x14}, p2sh.ScriptAddress()...)
+	p2shScript = append(p2shScript, 0x87)
+	p2pkhScript := hexToBytes("76a9141234567890abcdef1234567890abcdef12345" +
+		"67888ac")
+
+	// A pay-to-pubkey-hash signature script pushes a signature and a
+	// public key, while the multi-signature one pushes a dummy value, two
+	// signatures, and the redeem script.
+	sig := bytes.Repeat([]byte{0xac}, 71)
+	p2pkhSigScript := append([]byte{0x47}, sig...)
+	p2pkhSigScript = append(p2pkhSigScript, 0x21)
+	p2pkhSigScript = append(p2pkhSigScript, pubKeys[0]...)
+	p2shSigScript := []byte{0x00}
+	for i := 0; i < 2; i++ {
+		p2shSigScript = append(p2shSigScript, 0x47)
+		p2shSigScript = append(p2shSigScript, sig...)
+	}
+	p2shSigScript = append(p2shSigScript, 0x4c, byte(len(redeemScript)))
+	p2shSigScript = append(p2shSigScript, redeemScript...)
+
+	prevOuts := []wire.OutPoint{
+		{Hash: chainhash.Hash{0x01}, Index: 0},
+		{Hash: chainhash.Hash{0x02}, Index: 1},
+		{Hash: chainhash.Hash{0x03}, Index: 0},
+	}
+	prevScripts := map[wire.OutPoint][]byte{
+		prevOuts[0]: p2pkhScript,
+		prevOuts[1]: p2pkhScript,
+		prevOuts[2]: p2shScript,
+	}
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(&prevOuts[0], 0, p2pkhSigScript))
+	tx.AddTxIn(wire.NewTxIn(&prevOuts[1], 0, p2pkhSigScript))
+	tx.AddTxIn(wire.NewTxIn(&prevOuts[2], 0, p2shSigScript))
+	tx.AddTxOut(wire.NewTxOut(1e8, p2pkhScript))
+	tx.AddTxOut(wire.NewTxOut(1e8, p2shScript))
+	tx.AddTxOut(wire.NewTxOut(1e8, redeemScript))
+
+	// A coinbase with a signature script that happens to contain a
+	// signature checking opcode.
+	coinbase := wire.NewMsgTx()
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{},
+		wire.MaxPrevOutIndex, wire.TxTreeRegular), 0, []byte{0xac}))
+	coinbase.AddTxOut(wire.NewTxOut(1e8, p2pkhScript))
+
+	tests := []struct {
+		name        string
+		tx          *wire.MsgTx
+		prevScripts map[wire.OutPoint][]byte
+		want        int
+	}{
+		// The outputs count one for the pay-to-pubkey-hash script and
+		// the maximum of 20 for the bare multi-signature script.  The
+		// redeem script counts its 3 public keys.
+		{"p2pkh and p2sh multisig inputs", tx, prevScripts, 24},
+		{"unknown previous scripts", tx, nil, 21},
+		{"coinbase", coinbase, nil, 1},
+		{"vote", voteTx(), nil, 1},
+	}
+	for _, test := range tests {
+		got, err := hcutil.NewTx(test.tx).SigOpCount(test.prevScripts)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: mismatched count - got %d, want %d",
+				test.name, got, test.want)
+		}
+	}
+
+	// Ensure malformed scripts and pay-to-script-hash signature scripts
+	// which do not only push data are rejected.
+	malformedTx := tx.Copy()
+	malformedTx.TxIn[0].SignatureScript = []byte{0x4c}
+	nonPushTx := tx.Copy()
+	nonPushTx.TxIn[2].SignatureScript = append([]byte{0x76},
+		p2shSigScript...)
+	for _, errTx := range []*wire.MsgTx{malformedTx, nonPushTx} {
+		_, err := hcutil.NewTx(errTx).SigOpCount(prevScripts)
+		if err == nil {
+			t.Errorf("SigOpCount: expected error for tx %v",
+				errTx.TxHash())
+		}
+	}
+}
+
+// TestTxAllOutputsStandard ensures transactions are only reported as having
+// all standard outputs when every output script is of a standard type.
+func TestTxAllOutputsStandard(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := "1234567890abcdef1234567890abcdef12345678"
+
+	// standardTx returns a transaction with one output of every standard
+	// type.
+	standardTx := func() *wire.MsgTx {
+		tx := wire.NewMsgTx()
+		tx.AddTxOut(wire.NewTxOut(1000, hexToBytes("76a914"+hash+"88ac")))
+		tx.AddTxOut(wire.NewTxOut(1000, hexToBytes("a914"+hash+"87")))
+		tx.AddTxOut(wire.NewTxOut(0, hexToBytes("6a0401020304")))
+		tx.AddTxOut(wire.NewTxOut(1000, hexToBytes("512102192d74d0cb9434"+
+			"4c9569c2e77901573d8d7903c3ebec3a957724895dca52c6b451ae")))
+		return tx
+	}
+
+	ok, index := hcutil.NewTx(standardTx()).AllOutputsStandard(net)
+	if !ok || index != -1 {
+		t.Errorf("all standard: unexpected result - got (%v, %d), want "+
+			"(true, -1)", ok, index)
+	}
+
+	// An OP_RETURN output pushing more than the maximum allowed data is
+	// not standard.
+	oversized := standardTx()
+	oversized.TxOut[2].PkScript = append([]byte{0x6a, 0x4d, 0x01, 0x01},
+		bytes.Repeat([]byte{0x00}, 257)...)
+	ok, index = hcutil.NewTx(oversized).AllOutputsStandard(net)
+	if ok || index != 2 {
+		t.Errorf("oversized null data: unexpected result - got (%v, %d), "+
+			"want (false, 2)", ok, index)
+	}
+
+	// The index of the first non-standard output is reported.
+	nonStandard := standardTx()
+	nonStandard.TxOut[1].PkScript = hexToBytes("76a914")
+	nonStandard.TxOut[3].PkScript = hexToBytes("76a914")
+	ok, index = hcutil.NewTx(nonStandard).AllOutputsStandard(net)
+	if ok || index != 1 {
+		t.Errorf("non-standard: unexpected result - got (%v, %d), want "+
+			"(false, 1)", ok, index)
+	}
+}
+
+// TestTxReusedOutputAddresses ensures addresses paid to by more than one output
+// of a transaction are reported once each.
+func TestTxReusedOutputAddresses(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := "1234567890abcdef1234567890abcdef12345678"
+	otherHash := "0000000000000000000000000000000000000001"
+
+	tx := wire.NewMsgTx()
+	tx.AddTxOut(wire.NewTxOut(1000, hexToBytes("76a914"+hash+"88ac")))
+	tx.AddTxOut(wire.NewTxOut(1000, hexToBytes("a914"+hash+"87")))
+	tx.AddTxOut(wire.NewTxOut(0, hexToBytes("6a0401020304")))
+	tx.AddTxOut(wire.NewTxOut(1000, hexToBytes("76a914")))
+	tx.AddTxOut(wire.NewTxOut(1000, hexToBytes("76a914"+otherHash+"88ac")))
+
+	addrs, err := hcutil.NewTx(tx).ReusedOutputAddresses(net)
+	if err != nil {
+		t.Fatalf("ReusedOutputAddresses: unexpected error: %v", err)
+	}
+	if len(addrs) != 0 {
+		t.Errorf("ReusedOutputAddresses: unexpected reused addresses - "+
+			"got %v, want none", addrs)
+	}
+
+	// Pay the first address twice more along with repeating the null data
+	// and non-standard outputs, which must not be reported.
+	tx.AddTxOut(wire.NewTxOut(2000, hexToBytes("76a914"+hash+"88ac")))
+	tx.AddTxOut(wire.NewTxOut(0, hexToBytes("6a0401020304")))
+	tx.AddTxOut(wire.NewTxOut(1000, hexToBytes("76a914")))
+	tx.AddTxOut(wire.NewTxOut(3000, hexToBytes("76a914"+hash+"88ac")))
+
+	addrs, err = hcutil.NewTx(tx).ReusedOutputAddresses(net)
+	if err != nil {
+		t.Fatalf("ReusedOutputAddresses: unexpected error: %v", err)
+	}
+	want, err := hcutil.NewAddressPubKeyHash(hexToBytes(hash), net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	if len(addrs) != 1 || addrs[0].EncodeAddress() != want.EncodeAddress() {
+		t.Errorf("ReusedOutputAddresses: mismatched addresses - got %v, "+
+			"want [%v]", addrs, want)
+	}
+}
+
+// TestTxLikelyChangeIndex ensures outputs paying to known change addresses are
+// preferred and the round number and smaller value heuristics are applied
+// otherwise.
+func TestTxLikelyChangeIndex(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := "1234567890abcdef1234567890abcdef12345678"
+	otherHash := "0000000000000000000000000000000000000001"
+	changeAddr, err := hcutil.NewAddressPubKeyHash(hexToBytes(otherHash),
+		net, chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	known := hcutil.NewAddressSet(changeAddr)
+
+	tests := []struct {
+		name      string
+		values    [2]int64
+		known     *hcutil.AddressSet
+		wantIndex int
+		wantOK    bool
+	}{
+		// The second output pays to the known change address even though
+		// the heuristics would pick the first.
+		{"known address", [2]int64{12345678, 300000000}, known, 1, true},
+		{"round payment first", [2]int64{60000000, 39990000}, nil, 1, true},
+		{"round payment second", [2]int64{39990000, 60000000}, nil, 0, true},
+		{"both round", [2]int64{200000000, 100000000}, nil, 1, true},
+		{"neither round", [2]int64{12345678, 23456789}, nil, 0, true},
+		{"equal values", [2]int64{12345678, 12345678}, nil, -1, false},
+	}
+
+	for _, test := range tests {
+		tx := wire.NewMsgTx()
+		tx.AddTxOut(wire.NewTxOut(test.values[0],
+			hexToBytes("76a914"+hash+"88ac")))
+		tx.AddTxOut(wire.NewTxOut(test.values[1],
+			hexToBytes("76a914"+otherHash+"88ac")))
+
+		index, ok := hcutil.NewTx(tx).LikelyChangeIndex(test.known, net)
+		if index != test.wantIndex || ok != test.wantOK {
+			t.Errorf("%s: mismatched result - got (%d, %v), want "+
+				"(%d, %v)", test.name, index, ok, test.wantIndex,
+				test.wantOK)
+		}
+	}
+
+	// The heuristics only apply to transactions with two outputs.
+	tx := wire.NewMsgTx()
+	for i := 0; i < 3; i++ {
+		tx.AddTxOut(wire.NewTxOut(12345678, hexToBytes("76a914"+hash+"88ac")))
+	}
+	if index, ok := hcutil.NewTx(tx).LikelyChangeIndex(nil, net); ok {
+		t.Errorf("LikelyChangeIndex: unexpected change output %d for "+
+			"three outputs", index)
+	}
+}
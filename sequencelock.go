@@ -0,0 +1,94 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/wire"
+)
+
+// minSequenceLockTxVersion is the minimum transaction version for which
+// relative lock-times are enforced.
+const minSequenceLockTxVersion = 2
+
+// RelativeLockKind describes how the relative lock-time of a transaction input
+// is interpreted.
+type RelativeLockKind int
+
+const (
+	// RelativeLockDisabled indicates the input has no relative lock-time.
+	RelativeLockDisabled RelativeLockKind = iota
+
+	// RelativeLockBlocks indicates the input may not be included in a
+	// block until a number of blocks after the block containing the output
+	// it spends.
+	RelativeLockBlocks
+
+	// RelativeLockTime indicates the input may not be included in a block
+	// until a number of seconds after the median time of the block prior
+	// to the block containing the output it spends.
+	RelativeLockTime
+)
+
+// Map of RelativeLockKind values back to their constant names for pretty
+// printing.
+var relativeLockKindStrings = map[RelativeLockKind]string{
+	RelativeLockDisabled: "RelativeLockDisabled",
+	RelativeLockBlocks:   "RelativeLockBlocks",
+	RelativeLockTime:     "RelativeLockTime",
+}
+
+// String returns the RelativeLockKind as a human-readable name.
+func (k RelativeLockKind) String() string {
+	if s, ok := relativeLockKindStrings[k]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown RelativeLockKind (%d)", int(k))
+}
+
+// RelativeLock describes the relative lock-time of a transaction input.
+// Blocks is only set for RelativeLockBlocks and Seconds is only set for
+// RelativeLockTime.
+type RelativeLock struct {
+	Kind    RelativeLockKind
+	Blocks  uint32
+	Seconds int64
+}
+
+// RelativeTimelocks returns the relative lock-time of every input of the
+// transaction as interpreted from their sequence numbers per BIP68.  Time based
+// relative lock-times are converted to seconds.  An error is returned when the
+// transaction version is too low for relative lock-times to be enforced.
+func (t *Tx) RelativeTimelocks() ([]RelativeLock, error) {
+	if t.msgTx.Version < minSequenceLockTxVersion {
+		return nil, fmt.Errorf("transaction version %d does not enforce "+
+			"relative lock-times - minimum version is %d",
+			t.msgTx.Version, minSequenceLockTxVersion)
+	}
+
+	locks := make([]RelativeLock, 0, len(t.msgTx.TxIn))
+	for _, txIn := range t.msgTx.TxIn {
+		sequence := txIn.Sequence
+		value := sequence & wire.SequenceLockTimeMask
+		switch {
+		case sequence&wire.SequenceLockTimeDisabled != 0:
+			locks = append(locks, RelativeLock{
+				Kind: RelativeLockDisabled,
+			})
+		case sequence&wire.SequenceLockTimeIsSeconds != 0:
+			locks = append(locks, RelativeLock{
+				Kind:    RelativeLockTime,
+				Seconds: int64(value) << wire.SequenceLockTimeGranularity,
+			})
+		default:
+			locks = append(locks, RelativeLock{
+				Kind:   RelativeLockBlocks,
+				Blocks: value,
+			})
+		}
+	}
+	return locks, nil
+}
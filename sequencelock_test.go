@@ -0,0 +1,59 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestTxRelativeTimelocks ensures the sequence numbers of transaction inputs
+// are interpreted as relative lock-times as expected.
+func TestTxRelativeTimelocks(t *testing.T) {
+	sequences := []uint32{
+		wire.MaxTxInSequenceNum, // Disabled
+		1<<31 | 10,              // Disabled with value bits set
+		10,                      // 10 blocks
+		1<<22 | 3,               // 3 * 512 seconds
+		0xffff,                  // Maximum blocks
+		1<<16 | 5,               // Unused bits ignored
+	}
+	tx := wire.NewMsgTx()
+	tx.Version = 2
+	for i, sequence := range sequences {
+		prevOut := wire.NewOutPoint(&chainhash.Hash{byte(i)}, 0,
+			wire.TxTreeRegular)
+		txIn := wire.NewTxIn(prevOut, 0, nil)
+		txIn.Sequence = sequence
+		tx.AddTxIn(txIn)
+	}
+
+	want := []hcutil.RelativeLock{
+		{Kind: hcutil.RelativeLockDisabled},
+		{Kind: hcutil.RelativeLockDisabled},
+		{Kind: hcutil.RelativeLockBlocks, Blocks: 10},
+		{Kind: hcutil.RelativeLockTime, Seconds: 1536},
+		{Kind: hcutil.RelativeLockBlocks, Blocks: 0xffff},
+		{Kind: hcutil.RelativeLockBlocks, Blocks: 5},
+	}
+	locks, err := hcutil.NewTx(tx).RelativeTimelocks()
+	if err != nil {
+		t.Fatalf("RelativeTimelocks: unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(locks, want) {
+		t.Errorf("RelativeTimelocks: mismatched locks - got %+v, want %+v",
+			locks, want)
+	}
+
+	// Relative lock-times are not enforced for version 1 transactions.
+	tx.Version = 1
+	if _, err := hcutil.NewTx(tx).RelativeTimelocks(); err == nil {
+		t.Errorf("RelativeTimelocks: expected error for version 1")
+	}
+}
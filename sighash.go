@@ -0,0 +1,190 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+)
+
+// SigHashType represents the hash type bits at the end of a signature and
+// selects which parts of a transaction the signature commits to.
+type SigHashType byte
+
+// Hash type bits from the end of a signature.  They mirror the values defined
+// by the txscript package.
+const (
+	SigHashAll          SigHashType = 0x1
+	SigHashNone         SigHashType = 0x2
+	SigHashSingle       SigHashType = 0x3
+	SigHashAnyOneCanPay SigHashType = 0x80
+
+	// sigHashMask defines the number of bits of the hash type which is used
+	// to identify which outputs are signed.
+	sigHashMask = 0x1f
+)
+
+// These are the serialization types encoded in the upper 16 bits of the
+// version of the transaction prefix and witness serialized for signing.
+const (
+	sigHashSerializePrefix  = 1
+	sigHashSerializeWitness = 3
+)
+
+var (
+	// ErrSigHashInputIndex describes an error where the index of the input
+	// to calculate a signature hash for is out of range.
+	ErrSigHashInputIndex = errors.New("signature hash input index is out " +
+		"of range")
+
+	// ErrSigHashSingleIndex describes an error where SigHashSingle is used
+	// for an input which does not have a corresponding output.
+	ErrSigHashSingleIndex = errors.New("attempt to sign single input " +
+		"without a corresponding output")
+)
+
+// SigHash returns the signature hash of the input at the passed index of the
+// transaction for the passed hash type following the consensus rules of Hcd.
+// The prevScript is the public key script of the output spent by the input,
+// or the redeem script for pay-to-script-hash outputs, and any
+// OP_CODESEPARATOR opcodes are removed from it before it is committed to.
+//
+// The hash is the BLAKE-256 hash of the 4 byte little-endian hash type
+// followed by the hashes of the transaction prefix and of the signature
+// scripts as modified by the hash type.  Undefined hash types are treated as
+// SigHashAll as required by consensus.  The net parameter is not used by the
+// current rules.
+//
+// ErrSigHashInputIndex is returned when the input index is out of range and
+// ErrSigHashSingleIndex is returned when SigHashSingle is requested for an
+// input without a corresponding output.
+func (t *Tx) SigHash(inputIndex int, hashType SigHashType, prevScript []byte,
+	net *chaincfg.Params) (chainhash.Hash, error) {
+
+	msgTx := t.msgTx
+	if inputIndex < 0 || inputIndex >= len(msgTx.TxIn) {
+		return chainhash.Hash{}, ErrSigHashInputIndex
+	}
+	mask := hashType & sigHashMask
+	if mask == SigHashSingle && inputIndex >= len(msgTx.TxOut) {
+		return chainhash.Hash{}, ErrSigHashSingleIndex
+	}
+	signScript, err := removeCodeSeparators(prevScript)
+	if err != nil {
+		return chainhash.Hash{}, fmt.Errorf("invalid previous script: %v",
+			err)
+	}
+
+	// Choose the inputs and outputs committed to by the hash type.  When
+	// anyone can pay is set, only the input being signed is committed to.
+	txIns := msgTx.TxIn
+	signIdx := inputIndex
+	if hashType&SigHashAnyOneCanPay != 0 {
+		txIns = txIns[inputIndex : inputIndex+1]
+		signIdx = 0
+	}
+	txOuts := msgTx.TxOut
+	switch mask {
+	case SigHashNone:
+		txOuts = nil
+	case SigHashSingle:
+		txOuts = txOuts[:inputIndex+1]
+	}
+
+	// Writes to a bytes.Buffer can not fail, so the errors from the
+	// serialization below are not checked.
+	var prefix bytes.Buffer
+	putSigHashVersion(&prefix, msgTx.Version, sigHashSerializePrefix)
+	wire.WriteVarInt(&prefix, 0, uint64(len(txIns)))
+	for i, txIn := range txIns {
+		sequence := txIn.Sequence
+		if i != signIdx && (mask == SigHashNone || mask == SigHashSingle) {
+			sequence = 0
+		}
+		prevOut := &txIn.PreviousOutPoint
+		prefix.Write(prevOut.Hash[:])
+		binary.Write(&prefix, binary.LittleEndian, prevOut.Index)
+		prefix.WriteByte(byte(prevOut.Tree))
+		binary.Write(&prefix, binary.LittleEndian, sequence)
+	}
+	wire.WriteVarInt(&prefix, 0, uint64(len(txOuts)))
+	for i, txOut := range txOuts {
+		// Outputs other than the one at the index of the input being
+		// signed are blanked out for SigHashSingle.
+		value, pkScript := txOut.Value, txOut.PkScript
+		if mask == SigHashSingle && i != inputIndex {
+			value, pkScript = -1, nil
+		}
+		binary.Write(&prefix, binary.LittleEndian, value)
+		binary.Write(&prefix, binary.LittleEndian, txOut.Version)
+		wire.WriteVarBytes(&prefix, 0, pkScript)
+	}
+	binary.Write(&prefix, binary.LittleEndian, msgTx.LockTime)
+	binary.Write(&prefix, binary.LittleEndian, msgTx.Expiry)
+
+	// Only the signature script of the input being signed, which is
+	// replaced by the previous script, is committed to.
+	var witness bytes.Buffer
+	putSigHashVersion(&witness, msgTx.Version, sigHashSerializeWitness)
+	wire.WriteVarInt(&witness, 0, uint64(len(txIns)))
+	for i := range txIns {
+		var sigScript []byte
+		if i == signIdx {
+			sigScript = signScript
+		}
+		wire.WriteVarBytes(&witness, 0, sigScript)
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(hashType))
+	prefixHash := chainhash.HashH(prefix.Bytes())
+	witnessHash := chainhash.HashH(witness.Bytes())
+	buf.Write(prefixHash[:])
+	buf.Write(witnessHash[:])
+	return chainhash.HashH(buf.Bytes()), nil
+}
+
+// putSigHashVersion writes the transaction version with the passed
+// serialization type in the upper 16 bits.
+func putSigHashVersion(buf *bytes.Buffer, version uint16, serType uint32) {
+	binary.Write(buf, binary.LittleEndian, uint32(version)|serType<<16)
+}
+
+// removeCodeSeparators returns the passed script with all OP_CODESEPARATOR
+// opcodes removed.  Other opcodes, including their data pushes, are kept as
+// they are encoded in the script.  An error is returned for malformed scripts.
+func removeCodeSeparators(script []byte) ([]byte, error) {
+	pops, err := parseScript(script)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, 0, len(script))
+	offset := 0
+	for _, pop := range pops {
+		// The encoded length of an opcode is its opcode byte, any push
+		// length prefix and the data pushed.
+		size := 1 + len(pop.data)
+		switch pop.opcode {
+		case opPushData1:
+			size++
+		case opPushData2:
+			size += 2
+		case opPushData4:
+			size += 4
+		}
+		if pop.opcode != opCodeSeparator {
+			result = append(result, script[offset:offset+size]...)
+		}
+		offset += size
+	}
+	return result, nil
+}
@@ -0,0 +1,200 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcutil"
+)
+
+// sigHashTx returns a transaction with three inputs and two outputs used to
+// test signature hashes.
+func sigHashTx() *wire.MsgTx {
+	tx := wire.NewMsgTx()
+	for i := 0; i < 3; i++ {
+		prevOut := wire.NewOutPoint(&chainhash.Hash{byte(i + 1)},
+			uint32(i), int8(i%2))
+		txIn := wire.NewTxIn(prevOut, int64(i+1)*1e8, []byte{0x51})
+		txIn.Sequence = uint32(0xfffffff0 + i)
+		tx.AddTxIn(txIn)
+	}
+	tx.AddTxOut(wire.NewTxOut(150000000, hexToBytes("76a914"+
+		"2789d58cfa0957d206f025c2af056fc8a77cebb088ac")))
+	tx.AddTxOut(wire.NewTxOut(149990000, hexToBytes("a914"+
+		"f0b4e85100aee1a996f22915eb3c3f764d53779a87")))
+	tx.LockTime = 100
+	tx.Expiry = 200
+	return tx
+}
+
+// refSigHash calculates the signature hash of the passed transaction input by
+// modifying a copy of the transaction as required by the hash type and
+// hashing its prefix with the wire package.
+func refSigHash(tx *wire.MsgTx, idx int, hashType hcutil.SigHashType,
+	script []byte) chainhash.Hash {
+
+	txCopy := tx.Copy()
+	for i, txIn := range txCopy.TxIn {
+		txIn.SignatureScript = nil
+		if i == idx {
+			txIn.SignatureScript = script
+		}
+	}
+	switch hashType & 0x1f {
+	case hcutil.SigHashNone:
+		txCopy.TxOut = txCopy.TxOut[:0]
+		for i, txIn := range txCopy.TxIn {
+			if i != idx {
+				txIn.Sequence = 0
+			}
+		}
+	case hcutil.SigHashSingle:
+		txCopy.TxOut = txCopy.TxOut[:idx+1]
+		for i := 0; i < idx; i++ {
+			txCopy.TxOut[i].Value = -1
+			txCopy.TxOut[i].PkScript = nil
+		}
+		for i, txIn := range txCopy.TxIn {
+			if i != idx {
+				txIn.Sequence = 0
+			}
+		}
+	}
+	if hashType&hcutil.SigHashAnyOneCanPay != 0 {
+		txCopy.TxIn = txCopy.TxIn[idx : idx+1]
+	}
+
+	var witness bytes.Buffer
+	serVersion := uint32(txCopy.Version) | 3<<16
+	binary.Write(&witness, binary.LittleEndian, serVersion)
+	wire.WriteVarInt(&witness, 0, uint64(len(txCopy.TxIn)))
+	for _, txIn := range txCopy.TxIn {
+		wire.WriteVarBytes(&witness, 0, txIn.SignatureScript)
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, uint32(hashType))
+	prefixHash := txCopy.TxHash()
+	witnessHash := chainhash.HashH(witness.Bytes())
+	buf.Write(prefixHash[:])
+	buf.Write(witnessHash[:])
+	return chainhash.HashH(buf.Bytes())
+}
+
+// TestTxSigHash ensures signature hashes are calculated as expected for the
+// standard hash types and that invalid inputs are rejected.
+func TestTxSigHash(t *testing.T) {
+	params := &chaincfg.MainNetParams
+	prevScript := hexToBytes("76a9142789d58cfa0957d206f025c2af056fc8a77ce" +
+		"bb088ac")
+	hashTypes := []hcutil.SigHashType{
+		hcutil.SigHashAll,
+		hcutil.SigHashNone,
+		hcutil.SigHashSingle,
+		hcutil.SigHashAll | hcutil.SigHashAnyOneCanPay,
+		hcutil.SigHashNone | hcutil.SigHashAnyOneCanPay,
+		hcutil.SigHashSingle | hcutil.SigHashAnyOneCanPay,
+	}
+
+	msgTx := sigHashTx()
+	tx := hcutil.NewTx(msgTx)
+	for _, hashType := range hashTypes {
+		for idx := 0; idx < 2; idx++ {
+			got, err := tx.SigHash(idx, hashType, prevScript, params)
+			if err != nil {
+				t.Errorf("SigHash(%d, %#x): unexpected error: %v", idx,
+					hashType, err)
+				continue
+			}
+			want := refSigHash(msgTx, idx, hashType, prevScript)
+			if got != want {
+				t.Errorf("SigHash(%d, %#x): mismatched hash - got %v, "+
+					"want %v", idx, hashType, got, want)
+			}
+		}
+	}
+
+	// Ensure a known signature hash does not change.
+	got, err := tx.SigHash(0, hcutil.SigHashAll, prevScript, params)
+	if err != nil {
+		t.Fatalf("SigHash: unexpected error: %v", err)
+	}
+	want := "e20805d9ef2b1267399f31686978a07d04c82438d0d8e4011088494bf33f6999"
+	if got.String() != want {
+		t.Errorf("SigHash: mismatched hash - got %v, want %v", got, want)
+	}
+
+	// Ensure the transaction is not modified.
+	if !reflect.DeepEqual(msgTx, sigHashTx()) {
+		t.Errorf("SigHash: transaction was modified")
+	}
+
+	// Ensure OP_CODESEPARATOR opcodes are removed from the previous script.
+	sepScript := append([]byte{0xab}, prevScript...)
+	sepScript = append(sepScript, 0xab)
+	got, err = tx.SigHash(1, hcutil.SigHashAll, sepScript, params)
+	if err != nil {
+		t.Fatalf("SigHash: unexpected error: %v", err)
+	}
+	want2, _ := tx.SigHash(1, hcutil.SigHashAll, prevScript, params)
+	if got != want2 {
+		t.Errorf("SigHash: code separators were not removed - got %v, "+
+			"want %v", got, want2)
+	}
+
+	// Ensure the hash of inputs other than the one signed does not commit
+	// to their signature scripts or, for anyone can pay, their outpoints.
+	altTx := sigHashTx()
+	altTx.TxIn[0].SignatureScript = []byte{0x52}
+	altTx.TxIn[2].PreviousOutPoint.Index = 5
+	for _, hashType := range hashTypes {
+		got, _ := tx.SigHash(1, hashType, prevScript, params)
+		alt, _ := hcutil.NewTx(altTx).SigHash(1, hashType, prevScript,
+			params)
+		anyOneCanPay := hashType&hcutil.SigHashAnyOneCanPay != 0
+		if (got == alt) != anyOneCanPay {
+			t.Errorf("SigHash(%#x): unexpected commitment to other "+
+				"inputs", hashType)
+		}
+	}
+
+	// Ensure invalid requests are rejected.
+	errTests := []struct {
+		name     string
+		idx      int
+		hashType hcutil.SigHashType
+		script   []byte
+		wantErr  error
+	}{
+		{"negative index", -1, hcutil.SigHashAll, prevScript,
+			hcutil.ErrSigHashInputIndex},
+		{"index out of range", 3, hcutil.SigHashAll, prevScript,
+			hcutil.ErrSigHashInputIndex},
+		{"single without output", 2, hcutil.SigHashSingle, prevScript,
+			hcutil.ErrSigHashSingleIndex},
+		{"single anyone can pay without output", 2,
+			hcutil.SigHashSingle | hcutil.SigHashAnyOneCanPay, prevScript,
+			hcutil.ErrSigHashSingleIndex},
+		{"malformed script", 0, hcutil.SigHashAll, []byte{0x4c}, nil},
+	}
+	for _, test := range errTests {
+		_, err := tx.SigHash(test.idx, test.hashType, test.script, params)
+		if err == nil {
+			t.Errorf("%s: expected error", test.name)
+			continue
+		}
+		if test.wantErr != nil && err != test.wantErr {
+			t.Errorf("%s: mismatched error - got %v, want %v",
+				test.name, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/wire"
+)
+
+const (
+	// commitmentP2SHFlag is the bit set in the encoded amount of a ticket
+	// commitment output when the commitment pays to a script hash.
+	commitmentP2SHFlag = uint64(1) << 63
+
+	// commitmentAmountMask extracts the amount from the encoded amount of a
+	// ticket commitment output.
+	commitmentAmountMask = ^commitmentP2SHFlag
+
+	// defaultTicketFeeLimits are the fee limits encoded in the commitment
+	// outputs created by MakeTicketOutputs.  They are the same defaults used
+	// by the wallet.
+	defaultTicketFeeLimits = 0x5800
+
+	// commitmentDataLen is the length of the data pushed by a ticket
+	// commitment output, which consists of a 20 byte hash, an 8 byte
+	// amount, and 2 bytes of fee limits.
+	commitmentDataLen = 20 + 8 + 2
+)
+
+// StakeDifficultyError describes a stake amount which is outside of the
+// stake difficulty window.  The ErrorCode field identifies which bound was
+// violated and the remaining fields describe the amount and the window.
+type StakeDifficultyError struct {
+	ErrorCode ErrorCode
+	Amount    Amount
+	Min       Amount
+	Max       Amount
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e StakeDifficultyError) Error() string {
+	switch e.ErrorCode {
+	case ErrBelowStakeDifficulty:
+		return fmt.Sprintf("amount %v is below the minimum stake "+
+			"difficulty %v", e.Amount, e.Min)
+	case ErrAboveStakeDifficulty:
+		return fmt.Sprintf("amount %v is above the maximum stake "+
+			"difficulty %v", e.Amount, e.Max)
+	}
+	return fmt.Sprintf("amount %v is outside of the stake difficulty "+
+		"window [%v, %v]", e.Amount, e.Min, e.Max)
+}
+
+// ValidateStakeDifficulty returns an error when the passed amount is outside
+// of the inclusive stake difficulty window [min, max].  A StakeDifficultyError
+// with the ErrBelowStakeDifficulty or ErrAboveStakeDifficulty error code is
+// returned for amounts below and above the window, respectively.
+func ValidateStakeDifficulty(amt Amount, min, max Amount) error {
+	if min > max {
+		return fmt.Errorf("invalid stake difficulty window - minimum %v "+
+			"is greater than maximum %v", min, max)
+	}
+	if amt < min {
+		return StakeDifficultyError{ErrBelowStakeDifficulty, amt, min, max}
+	}
+	if amt > max {
+		return StakeDifficultyError{ErrAboveStakeDifficulty, amt, min, max}
+	}
+	return nil
+}
+
+// ErrNotTicket describes an error where a transaction is not of the form of a
+// ticket purchase (SStx) transaction.
+var ErrNotTicket = errors.New("transaction is not a ticket purchase")
+
+// isTicketPurchase returns whether or not the passed transaction has the
+// outputs of a ticket purchase, which consist of a ticket submission output
+// followed by one or more pairs of commitment and change outputs.
+func isTicketPurchase(msgTx *wire.MsgTx) bool {
+	numOuts := len(msgTx.TxOut)
+	if len(msgTx.TxIn) == 0 || numOuts < 3 || numOuts%2 != 1 {
+		return false
+	}
+	script := msgTx.TxOut[0].PkScript
+	if len(script) == 0 || script[0] != opSStx {
+		return false
+	}
+	for i := 1; i < numOuts; i += 2 {
+		data, ok := nullDataPush(msgTx.TxOut[i].PkScript)
+		if !ok || len(data) != commitmentDataLen {
+			return false
+		}
+		script := msgTx.TxOut[i+1].PkScript
+		if len(script) == 0 || script[0] != opSStxChange {
+			return false
+		}
+	}
+	return true
+}
+
+// TicketAddress returns the address committed to by the first commitment
+// output of the transaction, which must be a ticket purchase (SStx).
+// ErrNotTicket is returned for all other transactions.
+func (t *Tx) TicketAddress(net *chaincfg.Params) (Address, error) {
+	if !isTicketPurchase(t.msgTx) {
+		return nil, ErrNotTicket
+	}
+
+	data, _ := nullDataPush(t.msgTx.TxOut[1].PkScript)
+	encodedAmount := binary.LittleEndian.Uint64(data[20:28])
+	if encodedAmount&commitmentP2SHFlag != 0 {
+		return NewAddressScriptHashFromHash(data[:20], net)
+	}
+	return NewAddressPubKeyHash(data[:20], net, chainec.ECTypeSecp256k1)
+}
+
+// IsValidCommitmentAmount returns whether or not the passed amount can be
+// committed to by a ticket commitment output, which is the case when it is
+// neither negative nor greater than MaxAmount.  Every such amount fits in the
+// bits of the amount field that remain after reserving the most significant
+// bit for the script hash flag.
+func IsValidCommitmentAmount(amt Amount) bool {
+	return amt >= 0 && amt <= MaxAmount
+}
+
+// EncodeCommitmentAmount returns the passed amount encoded as it is in the
+// amount field of a ticket commitment output.  The most significant bit of the
+// field is reserved as a flag indicating the commitment pays to a script hash,
+// so it is always clear in the returned value and only the remaining bits
+// encode the amount.  The caller sets the flag when the commitment pays to a
+// script hash.
+//
+// The amount must be valid as determined by IsValidCommitmentAmount, which
+// callers must check first.  Invalid amounts are not rejected and only the
+// bits below the flag are kept, so, for example, -1 encodes as math.MaxInt64.
+func EncodeCommitmentAmount(amt Amount) int64 {
+	return int64(uint64(amt) & commitmentAmountMask)
+}
+
+// DecodeCommitmentAmount returns the amount encoded in the passed amount field
+// of a ticket commitment output.  Any flag bits are ignored, so the amount is
+// the same regardless of whether the commitment pays to a script hash.
+func DecodeCommitmentAmount(encoded int64) Amount {
+	return Amount(uint64(encoded) & commitmentAmountMask)
+}
+
+// commitmentScript returns the ticket commitment output script which commits
+// the passed amount to the passed address with the default fee limits.  The
+// amount must be valid as determined by IsValidCommitmentAmount.
+func commitmentScript(addr Address, amount Amount) ([]byte, error) {
+	hash, isScriptHash, err := stakeAddressHash(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedAmount := uint64(EncodeCommitmentAmount(amount))
+	if isScriptHash {
+		encodedAmount |= commitmentP2SHFlag
+	}
+	var data [commitmentDataLen]byte
+	copy(data[:20], hash)
+	binary.LittleEndian.PutUint64(data[20:28], encodedAmount)
+	binary.LittleEndian.PutUint16(data[28:], defaultTicketFeeLimits)
+
+	script := make([]byte, 0, 2+commitmentDataLen)
+	script = append(script, opReturn)
+	return addScriptData(script, data[:]), nil
+}
+
+// MakeTicketOutputs returns the outputs of a ticket purchase transaction in the
+// order they must appear: the ticket submission output paying the purchase
+// amount to the commitment address, the commitment output committing the
+// commitment amount to the commitment address, and the change output paying
+// the change amount to the change address.
+//
+// Both addresses must be secp256k1 pay-to-pubkey-hash or pay-to-script-hash
+// addresses.  The purchase and commitment amounts must be positive and the
+// change amount must not be negative.  No amount may exceed MaxAmount.
+func MakeTicketOutputs(purchase Amount, commitAddr Address, commitAmount Amount,
+	changeAddr Address, changeAmount Amount) ([]*wire.TxOut, error) {
+
+	if purchase <= 0 || purchase > MaxAmount {
+		return nil, fmt.Errorf("ticket purchase amount %v is out of range",
+			purchase)
+	}
+	if commitAmount <= 0 || commitAmount > MaxAmount {
+		return nil, fmt.Errorf("ticket commitment amount %v is out of "+
+			"range", commitAmount)
+	}
+	if changeAmount < 0 || changeAmount > MaxAmount {
+		return nil, fmt.Errorf("ticket change amount %v is out of range",
+			changeAmount)
+	}
+
+	submissionScript, err := PayToSStx(commitAddr)
+	if err != nil {
+		return nil, err
+	}
+	commitScript, err := commitmentScript(commitAddr, commitAmount)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := payToStakeTaggedScript(opSStxChange, changeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*wire.TxOut{
+		wire.NewTxOut(int64(purchase), submissionScript),
+		wire.NewTxOut(0, commitScript),
+		wire.NewTxOut(int64(changeAmount), changeScript),
+	}, nil
+}
@@ -0,0 +1,450 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestMakeTicketOutputs ensures the outputs of a ticket purchase are created
+// with the expected scripts and values and invalid parameters are rejected.
+func TestMakeTicketOutputs(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := hexToBytes("1234567890abcdef1234567890abcdef12345678")
+	p2pkh, err := hcutil.NewAddressPubKeyHash(hash, net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2pkhEd, err := hcutil.NewAddressPubKeyHash(hash, net,
+		chainec.ECTypeEdwards)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2sh, err := hcutil.NewAddressScriptHashFromHash(hash, net)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHashFromHash: unexpected error: %v", err)
+	}
+
+	type wantOut struct {
+		value  int64
+		script string
+	}
+	tests := []struct {
+		name         string
+		purchase     hcutil.Amount
+		commitAddr   hcutil.Address
+		commitAmount hcutil.Amount
+		changeAddr   hcutil.Address
+		changeAmount hcutil.Amount
+		want         []wantOut
+		wantErr      bool
+	}{
+		{
+			name:         "p2pkh commitment, p2sh change",
+			purchase:     100000000,
+			commitAddr:   p2pkh,
+			commitAmount: 100010000,
+			changeAddr:   p2sh,
+			changeAmount: 5000,
+			want: []wantOut{{
+				value: 100000000,
+				script: "ba76a9141234567890abcdef1234567890abcdef" +
+					"1234567888ac",
+			}, {
+				value: 0,
+				script: "6a1e1234567890abcdef1234567890abcdef1234" +
+					"56781008f605000000000058",
+			}, {
+				value: 5000,
+				script: "bda9141234567890abcdef1234567890abcdef12" +
+					"34567887",
+			}},
+		},
+		{
+			name:         "p2sh commitment, p2pkh zero change",
+			purchase:     100000000,
+			commitAddr:   p2sh,
+			commitAmount: 100000000,
+			changeAddr:   p2pkh,
+			changeAmount: 0,
+			want: []wantOut{{
+				value: 100000000,
+				script: "baa9141234567890abcdef1234567890abcdef12" +
+					"34567887",
+			}, {
+				value: 0,
+				script: "6a1e1234567890abcdef1234567890abcdef1234" +
+					"567800e1f505000000800058",
+			}, {
+				value: 0,
+				script: "bd76a9141234567890abcdef1234567890abcdef" +
+					"1234567888ac",
+			}},
+		},
+		{
+			name:         "zero purchase",
+			purchase:     0,
+			commitAddr:   p2pkh,
+			commitAmount: 100000000,
+			changeAddr:   p2pkh,
+			wantErr:      true,
+		},
+		{
+			name:         "zero commitment",
+			purchase:     100000000,
+			commitAddr:   p2pkh,
+			commitAmount: 0,
+			changeAddr:   p2pkh,
+			wantErr:      true,
+		},
+		{
+			name:         "negative change",
+			purchase:     100000000,
+			commitAddr:   p2pkh,
+			commitAmount: 100000000,
+			changeAddr:   p2pkh,
+			changeAmount: -1,
+			wantErr:      true,
+		},
+		{
+			name:         "purchase above max",
+			purchase:     hcutil.MaxAmount + 1,
+			commitAddr:   p2pkh,
+			commitAmount: 100000000,
+			changeAddr:   p2pkh,
+			wantErr:      true,
+		},
+		{
+			name:         "unsupported commitment address",
+			purchase:     100000000,
+			commitAddr:   p2pkhEd,
+			commitAmount: 100000000,
+			changeAddr:   p2pkh,
+			wantErr:      true,
+		},
+		{
+			name:         "missing change address",
+			purchase:     100000000,
+			commitAddr:   p2pkh,
+			commitAmount: 100000000,
+			changeAddr:   nil,
+			wantErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		outs, err := hcutil.MakeTicketOutputs(test.purchase,
+			test.commitAddr, test.commitAmount, test.changeAddr,
+			test.changeAmount)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if len(outs) != len(test.want) {
+			t.Errorf("%s: mismatched number of outputs - got %d, "+
+				"want %d", test.name, len(outs), len(test.want))
+			continue
+		}
+		for i, out := range outs {
+			want := test.want[i]
+			if out.Value != want.value {
+				t.Errorf("%s: output %d: mismatched value - got "+
+					"%d, want %d", test.name, i, out.Value,
+					want.value)
+			}
+			if !bytes.Equal(out.PkScript, hexToBytes(want.script)) {
+				t.Errorf("%s: output %d: mismatched script - got "+
+					"%x, want %s", test.name, i, out.PkScript,
+					want.script)
+			}
+		}
+	}
+}
+
+// TestTxTicketAddress ensures the committed address is extracted from ticket
+// purchases and other transactions are rejected.
+func TestTxTicketAddress(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	hash := hexToBytes("1234567890abcdef1234567890abcdef12345678")
+	p2pkh, err := hcutil.NewAddressPubKeyHash(hash, net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2sh, err := hcutil.NewAddressScriptHashFromHash(hash, net)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHashFromHash: unexpected error: %v", err)
+	}
+
+	// ticketTx returns a ticket purchase committing to the passed address
+	// with change to a different address.
+	ticketTx := func(commitAddr, changeAddr hcutil.Address) *wire.MsgTx {
+		outs, err := hcutil.MakeTicketOutputs(100000000, commitAddr,
+			100010000, changeAddr, 0)
+		if err != nil {
+			t.Fatalf("MakeTicketOutputs: unexpected error: %v", err)
+		}
+		tx := wire.NewMsgTx()
+		prevOut := wire.NewOutPoint(&chainhash.Hash{0x01}, 0,
+			wire.TxTreeRegular)
+		tx.AddTxIn(wire.NewTxIn(prevOut, 100010000, nil))
+		for _, out := range outs {
+			tx.AddTxOut(out)
+		}
+		return tx
+	}
+
+	for _, test := range []struct {
+		name       string
+		commitAddr hcutil.Address
+		changeAddr hcutil.Address
+	}{
+		{name: "p2pkh commitment", commitAddr: p2pkh, changeAddr: p2sh},
+		{name: "p2sh commitment", commitAddr: p2sh, changeAddr: p2pkh},
+	} {
+		tx := hcutil.NewTx(ticketTx(test.commitAddr, test.changeAddr))
+		addr, err := tx.TicketAddress(net)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if addr.EncodeAddress() != test.commitAddr.EncodeAddress() {
+			t.Errorf("%s: mismatched address - got %s, want %s",
+				test.name, addr.EncodeAddress(),
+				test.commitAddr.EncodeAddress())
+		}
+	}
+
+	// Ensure transactions which are not ticket purchases are rejected.
+	missingChange := ticketTx(p2pkh, p2pkh)
+	missingChange.TxOut = missingChange.TxOut[:2]
+	for _, test := range []struct {
+		name string
+		tx   *wire.MsgTx
+	}{
+		{name: "regular transaction", tx: p2pkhTx()},
+		{name: "vote", tx: voteTx()},
+		{name: "missing change", tx: missingChange},
+	} {
+		_, err := hcutil.NewTx(test.tx).TicketAddress(net)
+		if err != hcutil.ErrNotTicket {
+			t.Errorf("%s: mismatched error - got %v, want %v",
+				test.name, err, hcutil.ErrNotTicket)
+		}
+	}
+}
+
+// TestValidateStakeDifficulty ensures amounts are validated against the stake
+// difficulty window as expected, including at both bounds.
+func TestValidateStakeDifficulty(t *testing.T) {
+	const (
+		min hcutil.Amount = 2e8
+		max hcutil.Amount = 3e8
+	)
+	tests := []struct {
+		name    string
+		amt     hcutil.Amount
+		wantErr bool
+		code    hcutil.ErrorCode
+	}{
+		{name: "below minimum", amt: min - 1, wantErr: true,
+			code: hcutil.ErrBelowStakeDifficulty},
+		{name: "at minimum", amt: min},
+		{name: "within window", amt: (min + max) / 2},
+		{name: "at maximum", amt: max},
+		{name: "above maximum", amt: max + 1, wantErr: true,
+			code: hcutil.ErrAboveStakeDifficulty},
+	}
+	for _, test := range tests {
+		err := hcutil.ValidateStakeDifficulty(test.amt, min, max)
+		if !test.wantErr {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", test.name, err)
+			}
+			continue
+		}
+		want := hcutil.StakeDifficultyError{
+			ErrorCode: test.code,
+			Amount:    test.amt,
+			Min:       min,
+			Max:       max,
+		}
+		if err != want {
+			t.Errorf("%s: mismatched error - got %#v, want %#v",
+				test.name, err, want)
+		}
+	}
+
+	// Ensure an inverted window is rejected.
+	err := hcutil.ValidateStakeDifficulty(min, max, min)
+	if err == nil {
+		t.Errorf("inverted window: expected error")
+	}
+	if _, ok := err.(hcutil.StakeDifficultyError); ok {
+		t.Errorf("inverted window: unexpected stake difficulty error")
+	}
+}
+
+// TestCommitmentAmount ensures amounts round trip through the encoding used by
+// ticket commitments, including when the script hash flag is set by a
+// commitment output which pays to a script hash.
+func TestCommitmentAmount(t *testing.T) {
+	// p2shFlag is the flag bit set in the encoded amount of commitments
+	// which pay to a script hash.
+	const p2shFlag = math.MinInt64
+
+	tests := []struct {
+		name    string
+		amt     hcutil.Amount
+		encoded int64
+	}{
+		{name: "zero", amt: 0, encoded: 0},
+		{name: "one atom", amt: 1, encoded: 1},
+		{name: "one coin", amt: 1e8, encoded: 1e8},
+		{name: "max amount", amt: hcutil.MaxAmount, encoded: 21e15},
+	}
+	for _, test := range tests {
+		encoded := hcutil.EncodeCommitmentAmount(test.amt)
+		if encoded != test.encoded {
+			t.Errorf("%s: mismatched encoding - got %#x, want %#x",
+				test.name, encoded, test.encoded)
+		}
+		if encoded&p2shFlag != 0 {
+			t.Errorf("%s: flag bit set in encoding %#x", test.name,
+				encoded)
+		}
+		if amt := hcutil.DecodeCommitmentAmount(encoded); amt != test.amt {
+			t.Errorf("%s: mismatched decoded amount - got %d, want %d",
+				test.name, int64(amt), int64(test.amt))
+		}
+
+		// The flag must not affect the decoded amount.
+		flagged := encoded | p2shFlag
+		if amt := hcutil.DecodeCommitmentAmount(flagged); amt != test.amt {
+			t.Errorf("%s: mismatched decoded amount with flag set - "+
+				"got %d, want %d", test.name, int64(amt),
+				int64(test.amt))
+		}
+	}
+
+	// Ensure the amount encoded in the commitment outputs created by
+	// MakeTicketOutputs round trips with the flag set for script hash
+	// commitments and clear otherwise.
+	net := &chaincfg.MainNetParams
+	hash := hexToBytes("1234567890abcdef1234567890abcdef12345678")
+	p2pkh, err := hcutil.NewAddressPubKeyHash(hash, net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+	p2sh, err := hcutil.NewAddressScriptHashFromHash(hash, net)
+	if err != nil {
+		t.Fatalf("NewAddressScriptHashFromHash: unexpected error: %v", err)
+	}
+	commitTests := []struct {
+		name    string
+		addr    hcutil.Address
+		amt     hcutil.Amount
+		flagged bool
+	}{
+		{name: "p2pkh commitment", addr: p2pkh, amt: 1e8},
+		{name: "p2sh commitment", addr: p2sh, amt: 1e8, flagged: true},
+		{name: "p2sh max amount", addr: p2sh, amt: hcutil.MaxAmount,
+			flagged: true},
+	}
+	for _, test := range commitTests {
+		outs, err := hcutil.MakeTicketOutputs(test.amt, test.addr,
+			test.amt, p2pkh, 0)
+		if err != nil {
+			t.Errorf("%s: MakeTicketOutputs: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+
+		// The amount field follows the OP_RETURN, the data push opcode,
+		// and the 20 byte hash of the commitment script.
+		field := outs[1].PkScript[2+20 : 2+20+8]
+		encoded := int64(binary.LittleEndian.Uint64(field))
+		if flagged := encoded&p2shFlag != 0; flagged != test.flagged {
+			t.Errorf("%s: mismatched flag - got %v, want %v",
+				test.name, flagged, test.flagged)
+		}
+		if amt := hcutil.DecodeCommitmentAmount(encoded); amt != test.amt {
+			t.Errorf("%s: mismatched decoded amount - got %d, want %d",
+				test.name, int64(amt), int64(test.amt))
+		}
+	}
+
+	// Invalid amounts are masked to the bits below the flag rather than
+	// rejected, which is why callers must check IsValidCommitmentAmount
+	// first.
+	masked := []struct {
+		amt     hcutil.Amount
+		encoded int64
+	}{
+		{amt: -1, encoded: math.MaxInt64},
+		{amt: math.MinInt64, encoded: 0},
+	}
+	for _, test := range masked {
+		if hcutil.IsValidCommitmentAmount(test.amt) {
+			t.Errorf("amount %d: unexpectedly valid", int64(test.amt))
+		}
+		encoded := hcutil.EncodeCommitmentAmount(test.amt)
+		if encoded != test.encoded {
+			t.Errorf("amount %d: mismatched encoding - got %#x, want "+
+				"%#x", int64(test.amt), encoded, test.encoded)
+		}
+	}
+}
+
+// TestIsValidCommitmentAmount ensures only amounts which are neither negative
+// nor greater than MaxAmount are valid ticket commitment amounts.
+func TestIsValidCommitmentAmount(t *testing.T) {
+	tests := []struct {
+		name string
+		amt  hcutil.Amount
+		want bool
+	}{
+		{name: "negative", amt: -1, want: false},
+		{name: "min int64", amt: math.MinInt64, want: false},
+		{name: "zero", amt: 0, want: true},
+		{name: "max amount", amt: hcutil.MaxAmount, want: true},
+		{name: "max amount plus one", amt: hcutil.MaxAmount + 1, want: false},
+		{name: "max int64", amt: math.MaxInt64, want: false},
+	}
+	for _, test := range tests {
+		got := hcutil.IsValidCommitmentAmount(test.amt)
+		if got != test.want {
+			t.Errorf("%s: mismatched result - got %v, want %v",
+				test.name, got, test.want)
+		}
+		if !got {
+			continue
+		}
+
+		// Valid amounts must round trip through the encoding.
+		encoded := hcutil.EncodeCommitmentAmount(test.amt)
+		if amt := hcutil.DecodeCommitmentAmount(encoded); amt != test.amt {
+			t.Errorf("%s: mismatched decoded amount - got %d, want %d",
+				test.name, int64(amt), int64(test.amt))
+		}
+	}
+}
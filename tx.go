@@ -8,6 +8,8 @@ package hcutil
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"io"
 
@@ -24,15 +26,38 @@ var assertTransactionImmutability = false
 // yet.
 const TxIndexUnknown = -1
 
+// TooManyInputsError describes an error where a transaction has more inputs than
+// the maximum allowed.
+type TooManyInputsError struct {
+	NumInputs int // Number of inputs of the transaction
+	MaxInputs int // Maximum allowed number of inputs
+}
+
+// Error satisfies the error interface and prints human-readable errors.
+func (e TooManyInputsError) Error() string {
+	return fmt.Sprintf("transaction has too many inputs - got %d, max %d",
+		e.NumInputs, e.MaxInputs)
+}
+
+// TxValueReport describes the balance of the input and output values of a
+// transaction as returned by Tx.ValueReport.
+type TxValueReport struct {
+	TotalIn      Amount   // Sum of the input values
+	TotalOut     Amount   // Sum of the output values
+	Fee          Amount   // Input value not claimed by the outputs
+	OutputValues []Amount // Value of each output in order
+}
+
 // Tx defines a transaction that provides easier and more efficient manipulation
 // of raw transactions.  It also memoizes the hash for the transaction on its
 // first access so subsequent accesses don't have to repeat the relatively
 // expensive hashing operations.
 type Tx struct {
-	hash    chainhash.Hash // Cached transaction hash
-	msgTx   *wire.MsgTx    // Underlying MsgTx
-	txTree  int8           // Indicates which tx tree the tx is found in
-	txIndex int            // Position within a block or TxIndexUnknown
+	hash        chainhash.Hash  // Cached transaction hash
+	witnessHash *chainhash.Hash // Cached witness-inclusive hash
+	msgTx       *wire.MsgTx     // Underlying MsgTx
+	txTree      int8            // Indicates which tx tree the tx is found in
+	txIndex     int             // Position within a block or TxIndexUnknown
 }
 
 // MsgTx returns the underlying wire.MsgTx for the transaction.
@@ -58,6 +83,159 @@ func (t *Tx) Hash() *chainhash.Hash {
 	return &t.hash
 }
 
+// HasWitness returns whether or not the transaction carries witness data,
+// which is the case when it is serialized with its witness and at least one
+// input has a signature script.
+func (t *Tx) HasWitness() bool {
+	if t.msgTx.SerType == wire.TxSerializeNoWitness {
+		return false
+	}
+	for _, txIn := range t.msgTx.TxIn {
+		if len(txIn.SignatureScript) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// WitnessHash returns the hash of the transaction including its witness data.
+// This is equivalent to calling TxHashFull on the underlying wire.MsgTx,
+// however it caches the result so subsequent calls are more efficient.  Unlike
+// the hash returned by Hash, it commits to the signature scripts, so it changes
+// when the transaction is signed.
+func (t *Tx) WitnessHash() *chainhash.Hash {
+	if t.witnessHash == nil {
+		hash := t.msgTx.TxHashFull()
+		t.witnessHash = &hash
+	}
+	return t.witnessHash
+}
+
+// ShortID returns a compact identifier for the transaction which is suitable
+// for use as a database key.  It is the first 8 bytes of the transaction hash,
+// as returned by Hash, interpreted as a big-endian uint64.
+//
+// Since the identifier only retains 64 bits of the hash, it is not guaranteed
+// to be unique.  Randomly chosen transactions are expected to collide after
+// roughly 2^32 transactions, and an attacker can grind a collision with far
+// less effort than a full hash collision, so callers must be prepared to
+// handle multiple transactions sharing the same short identifier, for example
+// by falling back to comparing the full hash.
+func (t *Tx) ShortID() uint64 {
+	return binary.BigEndian.Uint64(t.Hash()[:8])
+}
+
+// ColorSeed returns bytes derived from the transaction hash which are suitable
+// for deterministically coloring the transaction in user interfaces, such as
+// two RGB colors.  It is the first 6 bytes of the transaction hash, as returned
+// by Hash, so it is stable for a given transaction and uniformly distributed
+// across transactions.
+func (t *Tx) ColorSeed() [6]byte {
+	var seed [6]byte
+	copy(seed[:], t.Hash()[:6])
+	return seed
+}
+
+// SizeBreakdown returns the serialized size of the transaction split into the
+// size of the non-witness portion, consisting of the version and prefix, and
+// the additional size of the witness portion, along with the total serialized
+// size.  The base and witness sizes always add up to the total size.
+//
+// The witness size is zero for transactions which are not serialized with
+// their witness data.
+func (t *Tx) SizeBreakdown() (base int, witness int, total int) {
+	total = t.msgTx.SerializeSize()
+	if t.msgTx.SerType != wire.TxSerializeFull {
+		return total, 0, total
+	}
+
+	prefix := *t.msgTx
+	prefix.SerType = wire.TxSerializeNoWitness
+	base = prefix.SerializeSize()
+	return base, total - base, total
+}
+
+// SerializeNoWitness returns the serialization of the transaction without its
+// witness data, which consists of the version and the transaction prefix.  It
+// is the preimage of the transaction hash and the base of the signature hash
+// preimage.  For transactions which are already serialized without their
+// witness data, it is the same as the full serialization.
+func (t *Tx) SerializeNoWitness() ([]byte, error) {
+	return t.msgTx.BytesPrefix()
+}
+
+// ToHex returns the hex encoding of the full serialization of the transaction.
+// It is the inverse of NewTxFromHex.
+func (t *Tx) ToHex() (string, error) {
+	serializedTx, err := t.msgTx.Bytes()
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(serializedTx), nil
+}
+
+// DuplicateOutputs returns the index pairs of every two outputs of the
+// transaction which pay the same value to the same script.  The first index of
+// each pair is always less than the second and the pairs are ordered by their
+// first and then second index.  An empty slice is returned when there are no
+// duplicate outputs.
+func (t *Tx) DuplicateOutputs() [][2]int {
+	dups := make([][2]int, 0)
+	txOuts := t.msgTx.TxOut
+	for i := 0; i < len(txOuts); i++ {
+		for j := i + 1; j < len(txOuts); j++ {
+			if txOuts[i].Value == txOuts[j].Value &&
+				txOuts[i].Version == txOuts[j].Version &&
+				bytes.Equal(txOuts[i].PkScript, txOuts[j].PkScript) {
+
+				dups = append(dups, [2]int{i, j})
+			}
+		}
+	}
+	return dups
+}
+
+// CheckInputCount returns a TooManyInputsError when the transaction has more
+// than the passed maximum number of inputs.
+func (t *Tx) CheckInputCount(max int) error {
+	if numInputs := len(t.msgTx.TxIn); numInputs > max {
+		return TooManyInputsError{NumInputs: numInputs, MaxInputs: max}
+	}
+	return nil
+}
+
+// ValueReport returns a report of the input and output values of the
+// transaction, where inputValues are the values of the outputs spent by each
+// input in order.  An error is returned when the number of input values does
+// not match the number of inputs or when the transaction spends more than the
+// value of its inputs.
+func (t *Tx) ValueReport(inputValues []Amount) (*TxValueReport, error) {
+	if len(inputValues) != len(t.msgTx.TxIn) {
+		return nil, fmt.Errorf("transaction has %d inputs, but %d input "+
+			"values were provided", len(t.msgTx.TxIn),
+			len(inputValues))
+	}
+
+	report := &TxValueReport{
+		OutputValues: make([]Amount, 0, len(t.msgTx.TxOut)),
+	}
+	for _, amt := range inputValues {
+		report.TotalIn += amt
+	}
+	for _, txOut := range t.msgTx.TxOut {
+		amt := Amount(txOut.Value)
+		report.TotalOut += amt
+		report.OutputValues = append(report.OutputValues, amt)
+	}
+	if report.TotalIn < report.TotalOut {
+		return nil, fmt.Errorf("transaction %v spends %v which is more "+
+			"than its input value of %v", t.Hash(), report.TotalOut,
+			report.TotalIn)
+	}
+	report.Fee = report.TotalIn - report.TotalOut
+	return report, nil
+}
+
 // Index returns the saved index of the transaction within a block.  This value
 // will be TxIndexUnknown if it hasn't already explicitly been set.
 func (t *Tx) Index() int {
@@ -80,6 +258,19 @@ func (t *Tx) SetTree(tree int8) {
 	t.txTree = tree
 }
 
+// OutPointKey returns a fixed size key for the passed outpoint which is suitable
+// for use as a map key, for example by UTXO caches.  The key consists of the
+// 32 byte transaction hash, the 4 byte output index encoded in little-endian
+// order, and the 1 byte transaction tree.  Equal outpoints always produce equal
+// keys and distinct outpoints always produce distinct keys.
+func OutPointKey(op wire.OutPoint) [37]byte {
+	var key [37]byte
+	copy(key[:chainhash.HashSize], op.Hash[:])
+	binary.LittleEndian.PutUint32(key[chainhash.HashSize:], op.Index)
+	key[chainhash.HashSize+4] = byte(op.Tree)
+	return key
+}
+
 // NewTx returns a new instance of a transaction given an underlying
 // wire.MsgTx.  See Tx.
 func NewTx(msgTx *wire.MsgTx) *Tx {
@@ -205,6 +396,28 @@ func NewTxFromBytes(serializedTx []byte) (*Tx, error) {
 	return NewTxFromReader(br)
 }
 
+// NewTxFromHex returns a new instance of a transaction given the hex encoding of
+// its serialized bytes, such as the hex passed to sendrawtransaction.  The
+// returned error describes whether the string is not valid hex or the decoded
+// bytes are not a valid transaction, including when there are bytes left over
+// after the transaction.  See Tx.
+func NewTxFromHex(s string) (*Tx, error) {
+	serializedTx, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid transaction hex: %v", err)
+	}
+	br := bytes.NewReader(serializedTx)
+	tx, err := NewTxFromReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("malformed transaction: %v", err)
+	}
+	if br.Len() != 0 {
+		return nil, fmt.Errorf("malformed transaction: %d unexpected "+
+			"trailing bytes", br.Len())
+	}
+	return tx, nil
+}
+
 // NewTxFromReader returns a new instance of a transaction given a
 // Reader to deserialize the transaction.  See Tx.
 func NewTxFromReader(r io.Reader) (*Tx, error) {
@@ -224,3 +437,65 @@ func NewTxFromReader(r io.Reader) (*Tx, error) {
 
 	return &t, nil
 }
+
+// SerializeTxs returns the serialized bytes of the passed transactions, each
+// prefixed by its serialized length encoded as a variable length integer, in
+// order.  The result is able to be decoded with DeserializeTxs.  An empty slice
+// of transactions serializes to no bytes.
+func SerializeTxs(txs []*Tx) ([]byte, error) {
+	size := 0
+	for _, tx := range txs {
+		txSize := tx.msgTx.SerializeSize()
+		size += wire.VarIntSerializeSize(uint64(txSize)) + txSize
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	for _, tx := range txs {
+		txSize := tx.msgTx.SerializeSize()
+		err := wire.WriteVarInt(buf, wire.ProtocolVersion, uint64(txSize))
+		if err != nil {
+			return nil, err
+		}
+		if err := tx.msgTx.Serialize(buf); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// DeserializeTxs returns the transactions serialized by SerializeTxs.  An error
+// is returned when a length prefix exceeds the remaining bytes or when the
+// bytes of a transaction do not exactly deserialize to a transaction.  See Tx.
+func DeserializeTxs(b []byte) ([]*Tx, error) {
+	var txs []*Tx
+	r := bytes.NewReader(b)
+	for r.Len() > 0 {
+		txSize, err := wire.ReadVarInt(r, wire.ProtocolVersion)
+		if err != nil {
+			return nil, fmt.Errorf("malformed transaction %d length: %v",
+				len(txs), err)
+		}
+		if txSize > uint64(r.Len()) {
+			return nil, fmt.Errorf("transaction %d length of %d bytes "+
+				"exceeds the %d remaining bytes", len(txs), txSize,
+				r.Len())
+		}
+
+		serializedTx := make([]byte, txSize)
+		if _, err := io.ReadFull(r, serializedTx); err != nil {
+			return nil, err
+		}
+		br := bytes.NewReader(serializedTx)
+		tx, err := NewTxFromReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("malformed transaction %d: %v",
+				len(txs), err)
+		}
+		if br.Len() != 0 {
+			return nil, fmt.Errorf("malformed transaction %d: %d "+
+				"unexpected trailing bytes", len(txs), br.Len())
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
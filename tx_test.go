@@ -8,11 +8,15 @@ package hcutil_test
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"io"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
 	"github.com/HcashOrg/hcutil"
 	"github.com/davecgh/go-spew/spew"
 )
@@ -103,6 +107,153 @@ func TestNewTxFromBytes(t *testing.T) {
 	}
 }
 
+// TestNewTxFromHex tests creation of a Tx from hex-encoded serialized bytes
+// and ensures malformed hex and transactions are rejected.
+func TestNewTxFromHex(t *testing.T) {
+	testTx := Block100000.Transactions[0]
+	testTxBytes, err := testTx.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: unexpected error: %v", err)
+	}
+	testTxHex := hex.EncodeToString(testTxBytes)
+
+	tx, err := hcutil.NewTxFromHex(testTxHex)
+	if err != nil {
+		t.Fatalf("NewTxFromHex: unexpected error: %v", err)
+	}
+	if msgTx := tx.MsgTx(); !reflect.DeepEqual(msgTx, testTx) {
+		t.Errorf("MsgTx: mismatched MsgTx - got %v, want %v",
+			spew.Sdump(msgTx), spew.Sdump(testTx))
+	}
+	if *tx.Hash() != testTx.TxHash() {
+		t.Errorf("Hash: mismatched hash - got %v, want %v", tx.Hash(),
+			testTx.TxHash())
+	}
+
+	tests := []struct {
+		name    string
+		hex     string
+		wantMsg string
+	}{
+		{"odd length", testTxHex[:len(testTxHex)-1], "invalid transaction hex"},
+		{"invalid character", "zz" + testTxHex[2:], "invalid transaction hex"},
+		{"empty", "", "malformed transaction"},
+		{"truncated", testTxHex[:8], "malformed transaction"},
+		{"trailing bytes", testTxHex + "00", "malformed transaction"},
+	}
+	for _, test := range tests {
+		_, err := hcutil.NewTxFromHex(test.hex)
+		if err == nil || !strings.HasPrefix(err.Error(), test.wantMsg) {
+			t.Errorf("%s: mismatched error - got %v, want prefix %q",
+				test.name, err, test.wantMsg)
+		}
+	}
+}
+
+// TestTxToHex ensures transactions round trip through their hex encoding.
+func TestTxToHex(t *testing.T) {
+	for i, msgTx := range []*wire.MsgTx{Block100000.Transactions[1],
+		p2pkhTx(), voteTx()} {
+
+		tx := hcutil.NewTx(msgTx)
+		txHex, err := tx.ToHex()
+		if err != nil {
+			t.Errorf("ToHex #%d: unexpected error: %v", i, err)
+			continue
+		}
+		decoded, err := hcutil.NewTxFromHex(txHex)
+		if err != nil {
+			t.Errorf("NewTxFromHex #%d: unexpected error: %v", i, err)
+			continue
+		}
+		reencoded, err := decoded.ToHex()
+		if err != nil {
+			t.Errorf("ToHex #%d: unexpected error: %v", i, err)
+			continue
+		}
+		if reencoded != txHex {
+			t.Errorf("#%d: mismatched hex - got %s, want %s", i,
+				reencoded, txHex)
+		}
+		if *decoded.Hash() != *tx.Hash() {
+			t.Errorf("#%d: mismatched hash - got %v, want %v", i,
+				decoded.Hash(), tx.Hash())
+		}
+	}
+}
+
+// TestSerializeTxs ensures batches of transactions round trip through their
+// length-prefixed serialization and malformed batches are rejected.
+func TestSerializeTxs(t *testing.T) {
+	var txs []*hcutil.Tx
+	for _, msgTx := range Block100000.Transactions {
+		txs = append(txs, hcutil.NewTx(msgTx))
+	}
+	txs = append(txs, hcutil.NewTx(p2pkhTx()), hcutil.NewTx(voteTx()))
+
+	for _, batch := range [][]*hcutil.Tx{nil, {}, txs[:1], txs} {
+		serialized, err := hcutil.SerializeTxs(batch)
+		if err != nil {
+			t.Errorf("SerializeTxs: unexpected error: %v", err)
+			continue
+		}
+		decoded, err := hcutil.DeserializeTxs(serialized)
+		if err != nil {
+			t.Errorf("DeserializeTxs: unexpected error: %v", err)
+			continue
+		}
+		if len(decoded) != len(batch) {
+			t.Errorf("DeserializeTxs: mismatched number of "+
+				"transactions - got %d, want %d", len(decoded),
+				len(batch))
+			continue
+		}
+		for i := range batch {
+			if *decoded[i].Hash() != *batch[i].Hash() {
+				t.Errorf("DeserializeTxs #%d: mismatched hash - "+
+					"got %v, want %v", i, decoded[i].Hash(),
+					batch[i].Hash())
+			}
+		}
+	}
+
+	// The serialization of a single transaction is its length followed by
+	// its serialized bytes.
+	varInt := func(n int) []byte {
+		var buf bytes.Buffer
+		wire.WriteVarInt(&buf, wire.ProtocolVersion, uint64(n))
+		return buf.Bytes()
+	}
+	serialized, err := hcutil.SerializeTxs(txs[1:2])
+	if err != nil {
+		t.Fatalf("SerializeTxs: unexpected error: %v", err)
+	}
+	txBytes, err := txs[1].MsgTx().Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: unexpected error: %v", err)
+	}
+	want := append(varInt(len(txBytes)), txBytes...)
+	if !bytes.Equal(serialized, want) {
+		t.Errorf("SerializeTxs: mismatched bytes - got %x, want %x",
+			serialized, want)
+	}
+
+	// Truncated batches, lengths which exceed the remaining bytes, and
+	// lengths which include bytes beyond the transaction are rejected.
+	malformed := [][]byte{
+		serialized[:len(serialized)-1],
+		append(varInt(len(txBytes)+1), txBytes...),
+		append(append(varInt(len(txBytes)+1), txBytes...), 0x00),
+		{0xfd},
+	}
+	for i, b := range malformed {
+		if _, err := hcutil.DeserializeTxs(b); err == nil {
+			t.Errorf("DeserializeTxs #%d: expected error for "+
+				"malformed batch", i)
+		}
+	}
+}
+
 // TestTxErrors tests the error paths for the Tx API.
 func TestTxErrors(t *testing.T) {
 	// Serialize the test transaction.
@@ -123,3 +274,318 @@ func TestTxErrors(t *testing.T) {
 			"got %v, want %v", err, io.EOF)
 	}
 }
+
+// TestTxWitness ensures transactions with signature scripts are reported as
+// having witness data and that the witness hash commits to it while the hash
+// does not.
+func TestTxWitness(t *testing.T) {
+	signed := p2pkhTx()
+	unsigned := p2pkhTx()
+	for _, txIn := range unsigned.TxIn {
+		txIn.SignatureScript = nil
+	}
+	signedTx := hcutil.NewTx(signed)
+	unsignedTx := hcutil.NewTx(unsigned)
+
+	if !signedTx.HasWitness() {
+		t.Errorf("HasWitness: signed transaction has no witness")
+	}
+	if unsignedTx.HasWitness() {
+		t.Errorf("HasWitness: unsigned transaction has witness")
+	}
+
+	// The hash only commits to the prefix, so it is the same for both,
+	// while the witness hash differs.
+	if *signedTx.Hash() != *unsignedTx.Hash() {
+		t.Errorf("Hash: mismatched hashes - got %v, want %v",
+			signedTx.Hash(), unsignedTx.Hash())
+	}
+	if *signedTx.WitnessHash() == *unsignedTx.WitnessHash() {
+		t.Errorf("WitnessHash: signed and unsigned witness hashes are "+
+			"both %v", signedTx.WitnessHash())
+	}
+	if want := signed.TxHashFull(); *signedTx.WitnessHash() != want {
+		t.Errorf("WitnessHash: mismatched hash - got %v, want %v",
+			signedTx.WitnessHash(), want)
+	}
+	if *signedTx.WitnessHash() == *signedTx.Hash() {
+		t.Errorf("WitnessHash: witness hash matches hash")
+	}
+
+	// The witness hash is cached.
+	if signedTx.WitnessHash() != signedTx.WitnessHash() {
+		t.Errorf("WitnessHash: hash was not cached")
+	}
+
+	// Transactions serialized without their witness do not carry it.
+	prefixOnly := p2pkhTx()
+	prefixOnly.SerType = wire.TxSerializeNoWitness
+	if hcutil.NewTx(prefixOnly).HasWitness() {
+		t.Errorf("HasWitness: prefix only transaction has witness")
+	}
+}
+
+// TestTxShortID ensures the short identifier of a transaction is deterministic
+// and derived from its hash.
+func TestTxShortID(t *testing.T) {
+	seen := make(map[uint64]int)
+	for i, msgTx := range Block100000.Transactions {
+		tx := hcutil.NewTx(msgTx)
+
+		// Request the short ID multiple times to ensure it is stable.
+		shortID := tx.ShortID()
+		if again := tx.ShortID(); again != shortID {
+			t.Errorf("ShortID #%d: unstable short ID - got %x, "+
+				"want %x", i, again, shortID)
+		}
+
+		// Ensure the short ID matches the first 8 bytes of the hash.
+		hash := tx.Hash()
+		want := binary.BigEndian.Uint64(hash[:8])
+		if shortID != want {
+			t.Errorf("ShortID #%d: mismatched short ID - got %x, "+
+				"want %x", i, shortID, want)
+		}
+
+		// Ensure a fresh wrapper of the same transaction agrees.
+		if other := hcutil.NewTx(msgTx).ShortID(); other != shortID {
+			t.Errorf("ShortID #%d: mismatched short ID for the "+
+				"same transaction - got %x, want %x", i, other,
+				shortID)
+		}
+
+		if j, ok := seen[shortID]; ok {
+			t.Errorf("ShortID #%d: unexpected collision with #%d",
+				i, j)
+		}
+		seen[shortID] = i
+	}
+}
+
+// TestTxColorSeed ensures the color seed of a transaction is deterministic and
+// differs between transactions.
+func TestTxColorSeed(t *testing.T) {
+	seen := make(map[[6]byte]int)
+	for i, msgTx := range Block100000.Transactions {
+		seed := hcutil.NewTx(msgTx).ColorSeed()
+		if again := hcutil.NewTx(msgTx).ColorSeed(); again != seed {
+			t.Errorf("ColorSeed #%d: unstable seed - got %x, want %x",
+				i, again, seed)
+		}
+		hash := msgTx.TxHash()
+		if !bytes.Equal(seed[:], hash[:6]) {
+			t.Errorf("ColorSeed #%d: mismatched seed - got %x, want "+
+				"%x", i, seed, hash[:6])
+		}
+
+		if j, ok := seen[seed]; ok {
+			t.Errorf("ColorSeed #%d: unexpected collision with #%d",
+				i, j)
+		}
+		seen[seed] = i
+	}
+}
+
+// TestTxSizeBreakdown ensures the serialized size of a transaction is split
+// into its base and witness portions as expected.
+func TestTxSizeBreakdown(t *testing.T) {
+	// The prefix of the transaction consists of the 4 byte version, a
+	// single 41 byte input prefix, two 36 byte outputs, the lock time,
+	// the expiry, and the counts.  The witness consists of the input
+	// count and a single input witness with a 107 byte signature script.
+	tx := hcutil.NewTx(p2pkhTx())
+	base, witness, total := tx.SizeBreakdown()
+	if base != 127 || witness != 125 || total != 252 {
+		t.Errorf("SizeBreakdown: mismatched sizes - got (%d, %d, %d), "+
+			"want (127, 125, 252)", base, witness, total)
+	}
+
+	// A transaction serialized without its witness data has no witness
+	// portion.
+	noWitness := p2pkhTx()
+	noWitness.SerType = wire.TxSerializeNoWitness
+	base, witness, total = hcutil.NewTx(noWitness).SizeBreakdown()
+	if base != 127 || witness != 0 || total != 127 {
+		t.Errorf("SizeBreakdown (no witness): mismatched sizes - got "+
+			"(%d, %d, %d), want (127, 0, 127)", base, witness, total)
+	}
+}
+
+// TestTxSerializeNoWitness ensures the serialization without witness data is
+// the transaction prefix and is the preimage of the transaction hash.
+func TestTxSerializeNoWitness(t *testing.T) {
+	// The version includes the no witness serialization type in the upper
+	// 16 bits and the input prefix omits the signature script.
+	pkScript := "76a914" + strings.Repeat("01", 20) + "88ac"
+	want := hexToBytes("01000100" + "01" +
+		"01" + strings.Repeat("00", 31) + "00000000" + "00" + "ffffffff" +
+		"02" +
+		"0087930300000000" + "0000" + "19" + pkScript +
+		"f032620200000000" + "0000" + "19" + pkScript +
+		"00000000" + "00000000")
+
+	msgTx := p2pkhTx()
+	tx := hcutil.NewTx(msgTx)
+	got, err := tx.SerializeNoWitness()
+	if err != nil {
+		t.Fatalf("SerializeNoWitness: unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("SerializeNoWitness: mismatched serialization - got %x, "+
+			"want %x", got, want)
+	}
+	if hash := chainhash.HashH(got); hash != *tx.Hash() {
+		t.Errorf("SerializeNoWitness: serialization does not hash to the "+
+			"transaction hash - got %v, want %v", hash, tx.Hash())
+	}
+
+	// A transaction serialized without its witness data has the same
+	// serialization.
+	noWitness := p2pkhTx()
+	noWitness.SerType = wire.TxSerializeNoWitness
+	full, err := noWitness.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: unexpected error: %v", err)
+	}
+	got, err = hcutil.NewTx(noWitness).SerializeNoWitness()
+	if err != nil {
+		t.Fatalf("SerializeNoWitness: unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, full) {
+		t.Errorf("SerializeNoWitness (no witness): mismatched "+
+			"serialization - got %x, want %x", got, full)
+	}
+}
+
+// TestTxDuplicateOutputs ensures outputs paying the same value to the same
+// script are detected.
+func TestTxDuplicateOutputs(t *testing.T) {
+	// The transaction pays different values to the same script, so it
+	// has no duplicates.
+	tx := p2pkhTx()
+	dups := hcutil.NewTx(tx).DuplicateOutputs()
+	if dups == nil || len(dups) != 0 {
+		t.Errorf("DuplicateOutputs: unexpected duplicates - got %v, "+
+			"want empty", dups)
+	}
+
+	// Add a duplicate of the first output along with an output paying
+	// the same value to a different script.
+	tx.AddTxOut(wire.NewTxOut(tx.TxOut[0].Value, []byte{0x51}))
+	tx.AddTxOut(wire.NewTxOut(tx.TxOut[0].Value, tx.TxOut[0].PkScript))
+	dups = hcutil.NewTx(tx).DuplicateOutputs()
+	want := [][2]int{{0, 3}}
+	if !reflect.DeepEqual(dups, want) {
+		t.Errorf("DuplicateOutputs: mismatched duplicates - got %v, "+
+			"want %v", dups, want)
+	}
+}
+
+// TestTxCheckInputCount ensures transactions are checked against the maximum
+// number of inputs, including at the boundary.
+func TestTxCheckInputCount(t *testing.T) {
+	tx := p2pkhTx()
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, 0, nil))
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 2}, 0, nil))
+	numInputs := len(tx.TxIn)
+	hcTx := hcutil.NewTx(tx)
+
+	if err := hcTx.CheckInputCount(numInputs); err != nil {
+		t.Errorf("CheckInputCount: unexpected error at the boundary: %v",
+			err)
+	}
+	if err := hcTx.CheckInputCount(numInputs + 1); err != nil {
+		t.Errorf("CheckInputCount: unexpected error below the "+
+			"boundary: %v", err)
+	}
+
+	err := hcTx.CheckInputCount(numInputs - 1)
+	want := hcutil.TooManyInputsError{
+		NumInputs: numInputs,
+		MaxInputs: numInputs - 1,
+	}
+	if err != want {
+		t.Errorf("CheckInputCount: mismatched error - got %v, want %v",
+			err, want)
+	}
+}
+
+// TestTxValueReport ensures the value report of a transaction balances its
+// input and output values and rejects mismatched or insufficient inputs.
+func TestTxValueReport(t *testing.T) {
+	tx := hcutil.NewTx(p2pkhTx())
+
+	report, err := tx.ValueReport([]hcutil.Amount{100000000})
+	if err != nil {
+		t.Fatalf("ValueReport: unexpected error: %v", err)
+	}
+	want := &hcutil.TxValueReport{
+		TotalIn:      100000000,
+		TotalOut:     99990000,
+		Fee:          10000,
+		OutputValues: []hcutil.Amount{60000000, 39990000},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("ValueReport: mismatched report - got %+v, want %+v",
+			report, want)
+	}
+
+	// The number of input values must match the number of inputs.
+	_, err = tx.ValueReport([]hcutil.Amount{50000000, 50000000})
+	if err == nil {
+		t.Errorf("ValueReport: expected error for mismatched input count")
+	}
+
+	// Spending more than the value of the inputs is an error.
+	_, err = tx.ValueReport([]hcutil.Amount{99989999})
+	if err == nil {
+		t.Errorf("ValueReport: expected error for insufficient inputs")
+	}
+}
+
+// TestOutPointKey ensures distinct outpoints produce distinct keys and equal
+// outpoints produce equal keys.
+func TestOutPointKey(t *testing.T) {
+	base := wire.OutPoint{
+		Hash:  chainhash.Hash{0x01, 0x02, 0x03},
+		Index: 1,
+		Tree:  wire.TxTreeRegular,
+	}
+	differentHash := base
+	differentHash.Hash[31] = 0xff
+	differentIndex := base
+	differentIndex.Index = 1 << 8
+	differentTree := base
+	differentTree.Tree = wire.TxTreeStake
+
+	keys := make(map[[37]byte]string)
+	for _, test := range []struct {
+		name string
+		op   wire.OutPoint
+	}{
+		{name: "base", op: base},
+		{name: "different hash", op: differentHash},
+		{name: "different index", op: differentIndex},
+		{name: "different tree", op: differentTree},
+	} {
+		key := hcutil.OutPointKey(test.op)
+		if other, ok := keys[key]; ok {
+			t.Errorf("%s: key collides with %s", test.name, other)
+			continue
+		}
+		keys[key] = test.name
+	}
+
+	// A copy of an outpoint must produce the same key.
+	same := base
+	if hcutil.OutPointKey(same) != hcutil.OutPointKey(base) {
+		t.Errorf("OutPointKey: equal outpoints produced distinct keys")
+	}
+
+	want := [37]byte{0x01, 0x02, 0x03}
+	want[32] = 0x01
+	if key := hcutil.OutPointKey(base); key != want {
+		t.Errorf("OutPointKey: mismatched key - got %x, want %x", key,
+			want)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"fmt"
+
+	"github.com/HcashOrg/hcd/wire"
+)
+
+// TxType describes the class of a transaction as shown by block explorers.
+type TxType int
+
+const (
+	// TxTypeRegular indicates a regular transaction.
+	TxTypeRegular TxType = iota
+
+	// TxTypeTicket indicates a ticket purchase (SStx) transaction.
+	TxTypeTicket
+
+	// TxTypeVote indicates a vote (SSGen) transaction.
+	TxTypeVote
+
+	// TxTypeRevocation indicates a ticket revocation (SSRtx) transaction.
+	TxTypeRevocation
+
+	// TxTypeCoinbase indicates a coinbase transaction.
+	TxTypeCoinbase
+)
+
+// Map of TxType values back to their display names for pretty printing.
+var txTypeStrings = map[TxType]string{
+	TxTypeRegular:    "Regular",
+	TxTypeTicket:     "Ticket",
+	TxTypeVote:       "Vote",
+	TxTypeRevocation: "Revocation",
+	TxTypeCoinbase:   "Coinbase",
+}
+
+// String returns the TxType as a human-readable name.
+func (t TxType) String() string {
+	if s, ok := txTypeStrings[t]; ok {
+		return s
+	}
+	return fmt.Sprintf("Unknown TxType (%d)", int(t))
+}
+
+// isCoinBase returns whether or not the passed transaction is a coinbase,
+// which has a single input that does not spend a previous output.
+func isCoinBase(msgTx *wire.MsgTx) bool {
+	return len(msgTx.TxIn) == 1 &&
+		isNullOutPoint(&msgTx.TxIn[0].PreviousOutPoint)
+}
+
+// isVote returns whether or not the passed transaction has the inputs and
+// outputs of a vote (SSGen).
+func isVote(msgTx *wire.MsgTx) bool {
+	_, _, _, err := ExtractVoteBits(msgTx)
+	return err == nil
+}
+
+// isRevocation returns whether or not the passed transaction has the inputs and
+// outputs of a ticket revocation (SSRtx), which spends a single ticket into
+// one or more stake revocation payments.
+func isRevocation(msgTx *wire.MsgTx) bool {
+	if len(msgTx.TxIn) != 1 || len(msgTx.TxOut) == 0 ||
+		msgTx.TxIn[0].PreviousOutPoint.Tree != wire.TxTreeStake {
+
+		return false
+	}
+	for _, txOut := range msgTx.TxOut {
+		if len(txOut.PkScript) == 0 || txOut.PkScript[0] != opSSRtx {
+			return false
+		}
+	}
+	return true
+}
+
+// Classify returns the class of the transaction.  Transactions which are not a
+// coinbase, ticket purchase, vote, or revocation are classified as regular.
+func (t *Tx) Classify() TxType {
+	switch {
+	case isVote(t.msgTx):
+		return TxTypeVote
+	case isCoinBase(t.msgTx):
+		return TxTypeCoinbase
+	case isTicketPurchase(t.msgTx):
+		return TxTypeTicket
+	case isRevocation(t.msgTx):
+		return TxTypeRevocation
+	}
+	return TxTypeRegular
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg"
+	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcutil"
+)
+
+// TestTxClassify ensures each class of transaction is identified as expected.
+func TestTxClassify(t *testing.T) {
+	net := &chaincfg.MainNetParams
+	addr, err := hcutil.NewAddressPubKeyHash(
+		hexToBytes("1234567890abcdef1234567890abcdef12345678"), net,
+		chainec.ECTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected error: %v", err)
+	}
+
+	ticket := wire.NewMsgTx()
+	ticket.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0,
+		wire.TxTreeRegular), 100010000, nil))
+	outs, err := hcutil.MakeTicketOutputs(100000000, addr, 100010000, addr,
+		0)
+	if err != nil {
+		t.Fatalf("MakeTicketOutputs: unexpected error: %v", err)
+	}
+	for _, out := range outs {
+		ticket.AddTxOut(out)
+	}
+
+	revocation := wire.NewMsgTx()
+	revocation.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0xaa},
+		0, wire.TxTreeStake), 100000000, nil))
+	revocation.AddTxOut(wire.NewTxOut(99990000, hexToBytes("bc76a914"+
+		"1234567890abcdef1234567890abcdef1234567888ac")))
+
+	coinbase := wire.NewMsgTx()
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{},
+		wire.MaxPrevOutIndex, wire.TxTreeRegular), 500000000,
+		[]byte{0x00, 0x00}))
+	coinbase.AddTxOut(wire.NewTxOut(500000000, hexToBytes("76a914"+
+		"1234567890abcdef1234567890abcdef1234567888ac")))
+
+	// A revocation spending a regular tree output is not a revocation.
+	regularSpend := revocation.Copy()
+	regularSpend.TxIn[0].PreviousOutPoint.Tree = wire.TxTreeRegular
+
+	tests := []struct {
+		name string
+		tx   *wire.MsgTx
+		want hcutil.TxType
+	}{
+		{name: "regular", tx: p2pkhTx(), want: hcutil.TxTypeRegular},
+		{name: "ticket", tx: ticket, want: hcutil.TxTypeTicket},
+		{name: "vote", tx: voteTx(), want: hcutil.TxTypeVote},
+		{name: "revocation", tx: revocation, want: hcutil.TxTypeRevocation},
+		{name: "coinbase", tx: coinbase, want: hcutil.TxTypeCoinbase},
+		{name: "revocation from regular tree", tx: regularSpend,
+			want: hcutil.TxTypeRegular},
+	}
+	for _, test := range tests {
+		got := hcutil.NewTx(test.tx).Classify()
+		if got != test.want {
+			t.Errorf("%s: mismatched type - got %v, want %v",
+				test.name, got, test.want)
+		}
+	}
+}
+
+// TestTxTypeStringer tests the stringized output for the TxType type.
+func TestTxTypeStringer(t *testing.T) {
+	tests := []struct {
+		in   hcutil.TxType
+		want string
+	}{
+		{hcutil.TxTypeRegular, "Regular"},
+		{hcutil.TxTypeTicket, "Ticket"},
+		{hcutil.TxTypeVote, "Vote"},
+		{hcutil.TxTypeRevocation, "Revocation"},
+		{hcutil.TxTypeCoinbase, "Coinbase"},
+		{0xff, "Unknown TxType (255)"},
+	}
+	for i, test := range tests {
+		if got := test.in.String(); got != test.want {
+			t.Errorf("String #%d: got %s, want %s", i, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+)
+
+const (
+	// voteBlockRefLen is the length of the data pushed by the first output
+	// of a vote, which consists of the hash and height of the block being
+	// voted on.
+	voteBlockRefLen = chainhash.HashSize + 4
+
+	// minVoteBitsLen and maxVoteBitsLen are the minimum and maximum
+	// lengths of the data pushed by the second output of a vote, which
+	// begins with the vote bits and is optionally followed by extended
+	// vote data.
+	minVoteBitsLen = 2
+	maxVoteBitsLen = opData75
+)
+
+// ErrNotVote describes an error where a transaction is not of the form of a
+// vote (SSGen) transaction.
+var ErrNotVote = errors.New("transaction is not a vote")
+
+// nullDataPush returns the data pushed by the passed script when it consists
+// of an OP_RETURN followed by a single direct data push.
+func nullDataPush(script []byte) ([]byte, bool) {
+	if len(script) < 2 || script[0] != opReturn ||
+		script[1] < opData1 || script[1] > opData75 ||
+		int(script[1]) != len(script)-2 {
+
+		return nil, false
+	}
+	return script[2:], true
+}
+
+// ExtractVoteBits returns the hash and height of the block voted on along with
+// the vote bits from the passed vote (SSGen) transaction.  ErrNotVote is
+// returned when the transaction does not have the inputs and outputs required
+// of a vote.
+func ExtractVoteBits(ssgen *wire.MsgTx) (chainhash.Hash, uint32, uint16, error) {
+	var blockHash chainhash.Hash
+	if len(ssgen.TxIn) != 2 || len(ssgen.TxOut) < 3 ||
+		!isNullOutPoint(&ssgen.TxIn[0].PreviousOutPoint) {
+
+		return blockHash, 0, 0, ErrNotVote
+	}
+
+	// Every output after the block reference and vote bits must be a
+	// stake generation payment.
+	for _, txOut := range ssgen.TxOut[2:] {
+		if len(txOut.PkScript) == 0 || txOut.PkScript[0] != opSSGen {
+			return blockHash, 0, 0, ErrNotVote
+		}
+	}
+
+	blockRef, ok := nullDataPush(ssgen.TxOut[0].PkScript)
+	if !ok || len(blockRef) != voteBlockRefLen {
+		return blockHash, 0, 0, ErrNotVote
+	}
+	voteBits, ok := nullDataPush(ssgen.TxOut[1].PkScript)
+	if !ok || len(voteBits) < minVoteBitsLen ||
+		len(voteBits) > maxVoteBitsLen {
+
+		return blockHash, 0, 0, ErrNotVote
+	}
+
+	copy(blockHash[:], blockRef[:chainhash.HashSize])
+	height := binary.LittleEndian.Uint32(blockRef[chainhash.HashSize:])
+	return blockHash, height, binary.LittleEndian.Uint16(voteBits), nil
+}
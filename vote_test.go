@@ -0,0 +1,123 @@
+// Copyright (c) 2018-2020 The Hcd developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package hcutil_test
+
+import (
+	"testing"
+
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/wire"
+	"github.com/HcashOrg/hcutil"
+)
+
+// voteTx returns a vote transaction for the block with hash 0x0102...20 at
+// height 100000 with vote bits 0x0005.
+func voteTx() *wire.MsgTx {
+	tx := wire.NewMsgTx()
+	stakebase := wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex,
+		wire.TxTreeRegular)
+	tx.AddTxIn(wire.NewTxIn(stakebase, 500000, []byte{0x00, 0x00}))
+	ticket := wire.NewOutPoint(&chainhash.Hash{0xaa}, 0, wire.TxTreeStake)
+	tx.AddTxIn(wire.NewTxIn(ticket, 100000000, nil))
+
+	tx.AddTxOut(wire.NewTxOut(0, hexToBytes("6a24"+
+		"0102030405060708090a0b0c0d0e0f10"+
+		"1112131415161718191a1b1c1d1e1f20"+"a0860100")))
+	tx.AddTxOut(wire.NewTxOut(0, hexToBytes("6a020500")))
+	tx.AddTxOut(wire.NewTxOut(100500000, hexToBytes("bb76a914"+
+		"1234567890abcdef1234567890abcdef1234567888ac")))
+	return tx
+}
+
+// TestExtractVoteBits ensures the block reference and vote bits are extracted
+// from vote transactions and other transactions are rejected.
+func TestExtractVoteBits(t *testing.T) {
+	tx := voteTx()
+	blockHash, height, voteBits, err := hcutil.ExtractVoteBits(tx)
+	if err != nil {
+		t.Fatalf("ExtractVoteBits: unexpected error: %v", err)
+	}
+	wantHash := chainhash.Hash{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08,
+		0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10,
+		0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17, 0x18,
+		0x19, 0x1a, 0x1b, 0x1c, 0x1d, 0x1e, 0x1f, 0x20,
+	}
+	if blockHash != wantHash {
+		t.Errorf("ExtractVoteBits: mismatched block hash - got %v, "+
+			"want %v", blockHash, wantHash)
+	}
+	if height != 100000 {
+		t.Errorf("ExtractVoteBits: mismatched height - got %d, want %d",
+			height, 100000)
+	}
+	if voteBits != 0x0005 {
+		t.Errorf("ExtractVoteBits: mismatched vote bits - got %#04x, "+
+			"want %#04x", voteBits, 0x0005)
+	}
+
+	// Extended vote data following the vote bits is permitted.
+	extended := voteTx()
+	extended.TxOut[1].PkScript = hexToBytes("6a0401000102")
+	_, _, voteBits, err = hcutil.ExtractVoteBits(extended)
+	if err != nil {
+		t.Fatalf("ExtractVoteBits (extended): unexpected error: %v", err)
+	}
+	if voteBits != 0x0001 {
+		t.Errorf("ExtractVoteBits (extended): mismatched vote bits - "+
+			"got %#04x, want %#04x", voteBits, 0x0001)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(tx *wire.MsgTx)
+	}{
+		{
+			name: "regular transaction",
+			modify: func(tx *wire.MsgTx) {
+				*tx = *p2pkhTx()
+			},
+		},
+		{
+			name: "missing stakebase",
+			modify: func(tx *wire.MsgTx) {
+				tx.TxIn[0].PreviousOutPoint.Index = 0
+			},
+		},
+		{
+			name: "short block reference",
+			modify: func(tx *wire.MsgTx) {
+				tx.TxOut[0].PkScript = hexToBytes("6a0401020304")
+			},
+		},
+		{
+			name: "missing vote bits",
+			modify: func(tx *wire.MsgTx) {
+				tx.TxOut[1].PkScript = hexToBytes("6a0105")
+			},
+		},
+		{
+			name: "untagged payment",
+			modify: func(tx *wire.MsgTx) {
+				tx.TxOut[2].PkScript = tx.TxOut[2].PkScript[1:]
+			},
+		},
+		{
+			name: "no payments",
+			modify: func(tx *wire.MsgTx) {
+				tx.TxOut = tx.TxOut[:2]
+			},
+		},
+	}
+	for _, test := range tests {
+		tx := voteTx()
+		test.modify(tx)
+		_, _, _, err := hcutil.ExtractVoteBits(tx)
+		if err != hcutil.ErrNotVote {
+			t.Errorf("%s: mismatched error - got %v, want %v",
+				test.name, err, hcutil.ErrNotVote)
+		}
+	}
+}
@@ -7,8 +7,13 @@
 package hcutil
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"io"
+	"strings"
 
 	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainec"
@@ -74,7 +79,8 @@ func (w *WIF) IsForNet(net *chaincfg.Params) bool {
 //
 // If the base58-decoded byte sequence does not match this, DecodeWIF will
 // return a non-nil error.  ErrMalformedPrivateKey is returned when the WIF
-// is of an impossible length.  ErrChecksumMismatch is returned if the
+// is of an impossible length, the DSA type is unknown, or the private key is
+// not valid for the DSA type.  ErrChecksumMismatch is returned if the
 // expected WIF checksum does not match the calculated checksum.
 func DecodeWIF(wif string) (*WIF, error) {
 	decoded := base58.Decode(wif)
@@ -95,29 +101,70 @@ func DecodeWIF(wif string) (*WIF, error) {
 	netID := [2]byte{decoded[0], decoded[1]}
 	var privKey chainec.PrivateKey
 
-	algType := 0
-	switch int(decoded[2]) {
+	// The private key must be of the length used by the signature
+	// algorithm.
+	algType := int(decoded[2])
+	privKeyBytes := decoded[3 : decodedLen-4]
+	privKeyLen := 32
+	if algType == bliss.BSTypeBliss {
+		privKeyLen = bliss.Bliss.PrivKeyBytesLen()
+	}
+	if len(privKeyBytes) != privKeyLen {
+		return nil, ErrMalformedPrivateKey
+	}
+
+	switch algType {
 	case chainec.ECTypeSecp256k1:
-		privKeyBytes := decoded[3 : 3+chainec.Secp256k1.PrivKeyBytesLen()]
 		privKey, _ = chainec.Secp256k1.PrivKeyFromScalar(privKeyBytes)
-		algType = chainec.ECTypeSecp256k1
 	case chainec.ECTypeEdwards:
-		privKeyBytes := decoded[3 : 3+32]
 		privKey, _ = chainec.Edwards.PrivKeyFromScalar(privKeyBytes)
-		algType = chainec.ECTypeEdwards
 	case chainec.ECTypeSecSchnorr:
-		privKeyBytes := decoded[3 : 3+chainec.SecSchnorr.PrivKeyBytesLen()]
 		privKey, _ = chainec.SecSchnorr.PrivKeyFromScalar(privKeyBytes)
-		algType = chainec.ECTypeSecSchnorr
 	case bliss.BSTypeBliss:
-		privKeyBytes := decoded[3 : 3+bliss.Bliss.PrivKeyBytesLen()]
 		privKey, _ = bliss.Bliss.PrivKeyFromBytes(privKeyBytes)
-		algType = bliss.BSTypeBliss
+	}
+	if privKey == nil {
+		return nil, ErrMalformedPrivateKey
 	}
 
 	return &WIF{algType, privKey, netID}, nil
 }
 
+// ImportWIFs reads WIF-encoded private keys from r, one per line, and returns
+// the decoded WIFs for the passed network.  Blank lines and lines beginning
+// with a '#' are skipped, as is any whitespace surrounding a key.  A line which
+// does not decode to a WIF for the passed network does not stop the import;
+// instead, an error describing the line number and problem is returned for
+// each such line along with the WIFs from all other lines.  An error reading
+// from r is returned as the final error.
+func ImportWIFs(r io.Reader, net *chaincfg.Params) ([]*WIF, []error) {
+	var wifs []*WIF
+	var errs []error
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		wif, err := DecodeWIF(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %v", lineNum, err))
+			continue
+		}
+		if !wif.IsForNet(net) {
+			errs = append(errs, fmt.Errorf("line %d: private key is "+
+				"not for %s", lineNum, net.Name))
+			continue
+		}
+		wifs = append(wifs, wif)
+	}
+	if err := scanner.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return wifs, errs
+}
+
 // String creates the Wallet Import Format string encoding of a WIF structure.
 // See DecodeWIF for a detailed breakdown of the format and requirements of
 // a valid WIF string.
@@ -167,6 +214,41 @@ func (w *WIF) SerializePubKey() []byte {
 	}
 }
 
+// Address returns the pay-to-pubkey-hash address for the passed network of the
+// public key associated with the private key.  The WIF format does not record
+// whether the public key was serialized compressed, so the address is always
+// that of the public key as serialized by SerializePubKey.  An error is
+// returned when the WIF is not for the passed network or its signature
+// algorithm is not known.
+func (w *WIF) Address(net *chaincfg.Params) (*AddressPubKeyHash, error) {
+	if !w.IsForNet(net) {
+		return nil, ErrWrongNetwork
+	}
+
+	switch w.AlgorithmType {
+	case chainec.ECTypeSecp256k1, chainec.ECTypeEdwards,
+		chainec.ECTypeSecSchnorr, bliss.BSTypeBliss:
+	default:
+		return nil, fmt.Errorf("unsupported signature algorithm %d",
+			w.AlgorithmType)
+	}
+	return NewAddressPubKeyHash(Hash160(w.SerializePubKey()), net,
+		w.AlgorithmType)
+}
+
+// AddressAndPubKey returns the pay-to-pubkey-hash address for the passed
+// network of the public key associated with the private key along with the
+// hex encoding of the serialized public key.  The public key is serialized as
+// done by SerializePubKey.  An error is returned when the WIF is not for the
+// passed network.
+func (w *WIF) AddressAndPubKey(net *chaincfg.Params) (*AddressPubKeyHash, string, error) {
+	addr, err := w.Address(net)
+	if err != nil {
+		return nil, "", err
+	}
+	return addr, hex.EncodeToString(w.SerializePubKey()), nil
+}
+
 // DSA returns the digital signature algorithm type for the private key.
 func (w *WIF) DSA() int {
 	return w.AlgorithmType
@@ -7,11 +7,16 @@
 package hcutil_test
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 
 	"github.com/HcashOrg/hcd/chaincfg"
 	"github.com/HcashOrg/hcd/chaincfg/chainec"
+	"github.com/HcashOrg/hcd/chaincfg/chainhash"
+	"github.com/HcashOrg/hcd/crypto/bliss"
 	. "github.com/HcashOrg/hcutil"
+	"github.com/HcashOrg/hcutil/base58"
 )
 
 func TestEncodeDecodeWIF(t *testing.T) {
@@ -160,3 +165,244 @@ func TestEncodeDecodeWIF(t *testing.T) {
 		}
 	}
 }
+
+// TestWIFPrivateKeyRoundTrip ensures Ed25519 and secp256k1 Schnorr private
+// keys decode to the same signature algorithm and private scalar they were
+// encoded with.
+func TestWIFPrivateKeyRoundTrip(t *testing.T) {
+	scalar := []byte{
+		0x0c, 0x28, 0xfc, 0xa3, 0x86, 0xc7, 0xa2, 0x27,
+		0x60, 0x0b, 0x2f, 0xe5, 0x0b, 0x7c, 0xae, 0x11,
+		0xec, 0x86, 0xd3, 0xbf, 0x1f, 0xbe, 0x47, 0x1b,
+		0xe8, 0x98, 0x27, 0xe1, 0x9d, 0x72, 0xaa, 0x1d}
+
+	edwardsKey, _ := chainec.Edwards.PrivKeyFromScalar(scalar)
+	schnorrKey, _ := chainec.SecSchnorr.PrivKeyFromScalar(scalar)
+	tests := []struct {
+		name    string
+		privKey chainec.PrivateKey
+		algo    int
+	}{
+		{"ed25519", edwardsKey, chainec.ECTypeEdwards},
+		{"schnorr", schnorrKey, chainec.ECTypeSecSchnorr},
+	}
+
+	for _, test := range tests {
+		w, err := NewWIF(test.privKey, &chaincfg.MainNetParams, test.algo)
+		if err != nil {
+			t.Errorf("%s: NewWIF: unexpected error: %v", test.name, err)
+			continue
+		}
+		decoded, err := DecodeWIF(w.String())
+		if err != nil {
+			t.Errorf("%s: DecodeWIF: unexpected error: %v", test.name,
+				err)
+			continue
+		}
+		if decoded.DSA() != test.algo {
+			t.Errorf("%s: mismatched algorithm - got %d, want %d",
+				test.name, decoded.DSA(), test.algo)
+		}
+		if !decoded.IsForNet(&chaincfg.MainNetParams) {
+			t.Errorf("%s: decoded WIF is not for mainnet", test.name)
+		}
+		got := decoded.PrivKey.Serialize()
+		if !bytes.Equal(got, scalar) {
+			t.Errorf("%s: mismatched private scalar - got %x, want %x",
+				test.name, got, scalar)
+		}
+		if decoded.String() != w.String() {
+			t.Errorf("%s: mismatched encoding - got %s, want %s",
+				test.name, decoded.String(), w.String())
+		}
+	}
+
+	// Keys with an unknown algorithm or a length which does not match the
+	// algorithm are malformed.
+	encode := func(algo int, privKey []byte) string {
+		netID := chaincfg.MainNetParams.PrivateKeyID
+		b := append([]byte{netID[0], netID[1], byte(algo)}, privKey...)
+		return base58.Encode(append(b, chainhash.HashB(b)[:4]...))
+	}
+	malformed := []string{
+		encode(0x7f, scalar),
+		encode(bliss.BSTypeBliss, scalar),
+		encode(chainec.ECTypeSecp256k1, make([]byte, 385)),
+	}
+	for _, wif := range malformed {
+		if _, err := DecodeWIF(wif); err != ErrMalformedPrivateKey {
+			t.Errorf("DecodeWIF: mismatched error - got %v, want %v",
+				err, ErrMalformedPrivateKey)
+		}
+	}
+}
+
+// TestWIFAddressAndPubKey ensures the address and public key derived from a
+// WIF are as expected and a network mismatch is rejected.
+func TestWIFAddressAndPubKey(t *testing.T) {
+	w, err := DecodeWIF("PmQdMn8xafwaQouk8ngs1CccRCB1ZmsqQxBaxNR4vhQi5a5QB5716")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, pubKey, err := w.AddressAndPubKey(&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPubKey := "02d0de0aaeaefad02b8bdc8a01a1b8b11c696bd3d66a2c5f10780d95b7df42645c"
+	if pubKey != wantPubKey {
+		t.Errorf("AddressAndPubKey: want pubkey '%s', got '%s'", wantPubKey,
+			pubKey)
+	}
+	wantAddr := "DsoJs2JhHNbY8pHT5SNK7ftaWnKMiZDJ9o4"
+	if got := addr.EncodeAddress(); got != wantAddr {
+		t.Errorf("AddressAndPubKey: want address '%s', got '%s'", wantAddr,
+			got)
+	}
+
+	// The WIF is for mainnet, so requesting a testnet address must fail.
+	_, _, err = w.AddressAndPubKey(&chaincfg.TestNet2Params)
+	if err != ErrWrongNetwork {
+		t.Errorf("AddressAndPubKey: want error '%v', got '%v'",
+			ErrWrongNetwork, err)
+	}
+}
+
+// TestWIFAddress ensures the pay-to-pubkey-hash address of the public key of a
+// WIF matches the known address for each supported signature algorithm.
+func TestWIFAddress(t *testing.T) {
+	tests := []struct {
+		name string
+		wif  string
+		net  *chaincfg.Params
+		want string
+	}{
+		{
+			name: "mainnet secp256k1",
+			wif:  "PmQdMn8xafwaQouk8ngs1CccRCB1ZmsqQxBaxNR4vhQi5a5QB5716",
+			net:  &chaincfg.MainNetParams,
+			want: "DsoJs2JhHNbY8pHT5SNK7ftaWnKMiZDJ9o4",
+		},
+		{
+			name: "mainnet ed25519",
+			wif:  "PmQfJXKC2ho1633ZiVbSdCZw1y68BVXYFpyE2UfDcbQN5xa3DByDn",
+			net:  &chaincfg.MainNetParams,
+			want: "DeuuEGN4rhSUYxV7BGTw5pyTRBPTcLigCxz",
+		},
+		{
+			name: "mainnet schnorr",
+			wif:  "PmQhFGVRUjeRmGBPJCW2FCXFck1EoDBF6hks6auNJVQ26M4h73W9W",
+			net:  &chaincfg.MainNetParams,
+			want: "DSrMTyaDRMQop7QohvMfMc7hjm3PEVkbt74",
+		},
+		{
+			name: "testnet secp256k1",
+			wif:  "PtWVDUidYaiiNT5e2Sfb1Ah4evbaSopZJkkpFBuzkJYcYteugvdFg",
+			net:  &chaincfg.TestNet2Params,
+			want: "TsTT6JhJCCnGt7XkfuBamNavqr2zvWFWCYu",
+		},
+		{
+			name: "testnet ed25519",
+			wif:  "PtWVaBGeCfbFQfgqFew8YvdrSH5TH439K7rvpo3aWnSfDvyK8ijbK",
+			net:  &chaincfg.TestNet2Params,
+			want: "Teg46VghkVFNMiXA2L5fwiwjsMC3iVMNf9o",
+		},
+		{
+			name: "testnet schnorr",
+			wif:  "PtWZ6y56SeRZiuMHBrUkFAbhrURogF7xzWL6PQQJ86XvZfeE3jf1a",
+			net:  &chaincfg.TestNet2Params,
+			want: "TSWVhFxpLBbYZQf7JPAw1Jp44pm2SSdQ6tr",
+		},
+	}
+
+	for _, test := range tests {
+		w, err := DecodeWIF(test.wif)
+		if err != nil {
+			t.Errorf("%s: DecodeWIF: unexpected error: %v", test.name, err)
+			continue
+		}
+		addr, err := w.Address(test.net)
+		if err != nil {
+			t.Errorf("%s: Address: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got := addr.EncodeAddress(); got != test.want {
+			t.Errorf("%s: Address: want '%s', got '%s'", test.name,
+				test.want, got)
+		}
+		if addr.DSA(test.net) != w.DSA() {
+			t.Errorf("%s: Address: want algorithm %d, got %d",
+				test.name, w.DSA(), addr.DSA(test.net))
+		}
+
+		// The address must be the same one returned along with the
+		// public key.
+		addr2, _, err := w.AddressAndPubKey(test.net)
+		if err != nil {
+			t.Errorf("%s: AddressAndPubKey: unexpected error: %v",
+				test.name, err)
+			continue
+		}
+		if addr2.EncodeAddress() != addr.EncodeAddress() {
+			t.Errorf("%s: AddressAndPubKey: want address '%s', got "+
+				"'%s'", test.name, addr, addr2)
+		}
+	}
+
+	w, err := DecodeWIF(tests[0].wif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Address(&chaincfg.TestNet2Params); err != ErrWrongNetwork {
+		t.Errorf("Address: want error '%v', got '%v'", ErrWrongNetwork,
+			err)
+	}
+
+	// Unknown signature algorithms are rejected.
+	w.AlgorithmType = 99
+	if _, err := w.Address(&chaincfg.MainNetParams); err == nil {
+		t.Errorf("Address: expected error for unknown algorithm")
+	}
+}
+
+// TestImportWIFs ensures valid keys are imported from a newline-separated list
+// with blank lines and comments skipped, and invalid keys are reported along
+// with their line numbers.
+func TestImportWIFs(t *testing.T) {
+	input := `# Keys to import
+PmQdMn8xafwaQouk8ngs1CccRCB1ZmsqQxBaxNR4vhQi5a5QB5716
+
+  PmQfJXKC2ho1633ZiVbSdCZw1y68BVXYFpyE2UfDcbQN5xa3DByDn
+PmQdMn8xafwaQouk8ngs1CccRCB1ZmsqQxBaxNR4vhQi5a5QB5717
+PtWVDUidYaiiNT5e2Sfb1Ah4evbaSopZJkkpFBuzkJYcYteugvdFg
+`
+	wifs, errs := ImportWIFs(strings.NewReader(input), &chaincfg.MainNetParams)
+
+	want := []string{
+		"PmQdMn8xafwaQouk8ngs1CccRCB1ZmsqQxBaxNR4vhQi5a5QB5716",
+		"PmQfJXKC2ho1633ZiVbSdCZw1y68BVXYFpyE2UfDcbQN5xa3DByDn",
+	}
+	if len(wifs) != len(want) {
+		t.Fatalf("ImportWIFs: want %d keys, got %d", len(want), len(wifs))
+	}
+	for i, wif := range wifs {
+		if got := wif.String(); got != want[i] {
+			t.Errorf("ImportWIFs: key %d: want '%s', got '%s'", i,
+				want[i], got)
+		}
+	}
+
+	// The key with a bad checksum and the testnet key are reported along
+	// with their line numbers.
+	wantErrs := []string{"line 5: ", "line 6: "}
+	if len(errs) != len(wantErrs) {
+		t.Fatalf("ImportWIFs: want %d errors, got %d: %v", len(wantErrs),
+			len(errs), errs)
+	}
+	for i, err := range errs {
+		if !strings.HasPrefix(err.Error(), wantErrs[i]) {
+			t.Errorf("ImportWIFs: error %d: want prefix '%s', got '%v'",
+				i, wantErrs[i], err)
+		}
+	}
+}